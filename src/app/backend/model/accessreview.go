@@ -0,0 +1,222 @@
+package model
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kore3lab/dashboard/pkg/config"
+	authorizationV1 "k8s.io/api/authorization/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	SUBJECT_KIND_USER            = "User"
+	SUBJECT_KIND_GROUP           = "Group"
+	SUBJECT_KIND_SERVICE_ACCOUNT = "ServiceAccount"
+)
+
+// a subject (user, group, service account), nil = current user (dashboard credentials)
+type AccessSubject struct {
+	Kind      string   `json:"kind"`
+	Name      string   `json:"name"`
+	Namespace string   `json:"namespace"` // service account namespace
+	Groups    []string `json:"groups"`    // additional groups (user)
+}
+
+// resource attributes ("can-i <verb> <resource>")
+type AccessAttributes struct {
+	Verb        string `json:"verb"`
+	Group       string `json:"group"`
+	Resource    string `json:"resource"`
+	Subresource string `json:"subresource"`
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	Path        string `json:"path"` // non-resource url (eg. "/healthz")
+}
+
+type AccessReviewRequest struct {
+	Subject    *AccessSubject     `json:"subject"`
+	Attributes []AccessAttributes `json:"attributes"`
+}
+
+type AccessReviewResult struct {
+	AccessAttributes
+	Allowed         bool   `json:"allowed"`
+	Denied          bool   `json:"denied"`
+	Reason          string `json:"reason"`
+	EvaluationError string `json:"evaluationError"`
+}
+
+type AccessRulesResult struct {
+	Subject          *AccessSubject                    `json:"subject"`
+	Namespace        string                            `json:"namespace"`
+	ResourceRules    []authorizationV1.ResourceRule    `json:"resourceRules"`
+	NonResourceRules []authorizationV1.NonResourceRule `json:"nonResourceRules"`
+	Incomplete       bool                              `json:"incomplete"`
+	EvaluationError  string                            `json:"evaluationError"`
+}
+
+// review accesses of a subject (SubjectAccessReview) or current user (SelfSubjectAccessReview)
+func ReviewAccess(cluster string, req AccessReviewRequest) ([]AccessReviewResult, error) {
+
+	if len(req.Attributes) == 0 {
+		return nil, fmt.Errorf("attributes are empty")
+	}
+
+	user, groups, err := req.Subject.userInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := config.Cluster.Client(cluster)
+	if err != nil {
+		return nil, err
+	}
+	apiClient, err := client.NewKubernetesClient()
+	if err != nil {
+		return nil, err
+	}
+
+	results := []AccessReviewResult{}
+	for _, attr := range req.Attributes {
+		if attr.Verb == "" {
+			return nil, fmt.Errorf("verb is empty")
+		}
+
+		var status authorizationV1.SubjectAccessReviewStatus
+		if req.Subject == nil {
+			r, err := apiClient.AuthorizationV1().SelfSubjectAccessReviews().Create(context.TODO(), &authorizationV1.SelfSubjectAccessReview{
+				Spec: authorizationV1.SelfSubjectAccessReviewSpec{
+					ResourceAttributes:    attr.resourceAttributes(),
+					NonResourceAttributes: attr.nonResourceAttributes(),
+				},
+			}, metaV1.CreateOptions{})
+			if err != nil {
+				return nil, err
+			}
+			status = r.Status
+		} else {
+			r, err := apiClient.AuthorizationV1().SubjectAccessReviews().Create(context.TODO(), &authorizationV1.SubjectAccessReview{
+				Spec: authorizationV1.SubjectAccessReviewSpec{
+					User:                  user,
+					Groups:                groups,
+					ResourceAttributes:    attr.resourceAttributes(),
+					NonResourceAttributes: attr.nonResourceAttributes(),
+				},
+			}, metaV1.CreateOptions{})
+			if err != nil {
+				return nil, err
+			}
+			status = r.Status
+		}
+
+		results = append(results, AccessReviewResult{
+			AccessAttributes: attr,
+			Allowed:          status.Allowed,
+			Denied:           status.Denied,
+			Reason:           status.Reason,
+			EvaluationError:  status.EvaluationError,
+		})
+	}
+
+	return results, nil
+
+}
+
+// effective rules of a subject in a namespace (SelfSubjectRulesReview, impersonated if a subject is specified)
+func ReviewRules(cluster string, namespace string, subject *AccessSubject) (*AccessRulesResult, error) {
+
+	user, groups, err := subject.userInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := config.Cluster.Client(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	restConfig := client.RESTConfig
+	if subject != nil {
+		// impersonate (requires "impersonate" permission)
+		restConfig = rest.CopyConfig(client.RESTConfig)
+		if user == "" {
+			// impersonating groups requires a username
+			user = "system:anonymous"
+		}
+		restConfig.Impersonate = rest.ImpersonationConfig{UserName: user, Groups: groups}
+	}
+	apiClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := apiClient.AuthorizationV1().SelfSubjectRulesReviews().Create(context.TODO(), &authorizationV1.SelfSubjectRulesReview{
+		Spec: authorizationV1.SelfSubjectRulesReviewSpec{Namespace: namespace},
+	}, metaV1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &AccessRulesResult{
+		Subject:          subject,
+		Namespace:        namespace,
+		ResourceRules:    r.Status.ResourceRules,
+		NonResourceRules: r.Status.NonResourceRules,
+		Incomplete:       r.Status.Incomplete,
+		EvaluationError:  r.Status.EvaluationError,
+	}, nil
+
+}
+
+// username & groups of a subject
+func (me *AccessSubject) userInfo() (string, []string, error) {
+
+	if me == nil {
+		return "", nil, nil
+	}
+	if me.Name == "" {
+		return "", nil, fmt.Errorf("subject name is empty")
+	}
+
+	switch me.Kind {
+	case SUBJECT_KIND_USER, "":
+		return me.Name, me.Groups, nil
+	case SUBJECT_KIND_GROUP:
+		return "", append([]string{me.Name}, me.Groups...), nil
+	case SUBJECT_KIND_SERVICE_ACCOUNT:
+		if me.Namespace == "" {
+			return "", nil, fmt.Errorf("service account namespace is empty")
+		}
+		return fmt.Sprintf("system:serviceaccount:%s:%s", me.Namespace, me.Name), []string{
+			"system:serviceaccounts",
+			fmt.Sprintf("system:serviceaccounts:%s", me.Namespace),
+			"system:authenticated",
+		}, nil
+	}
+	return "", nil, fmt.Errorf("unsupported subject kind '%s'", me.Kind)
+
+}
+
+func (me AccessAttributes) resourceAttributes() *authorizationV1.ResourceAttributes {
+	if me.Path != "" {
+		return nil
+	}
+	return &authorizationV1.ResourceAttributes{
+		Namespace:   me.Namespace,
+		Verb:        me.Verb,
+		Group:       me.Group,
+		Resource:    me.Resource,
+		Subresource: me.Subresource,
+		Name:        me.Name,
+	}
+}
+
+func (me AccessAttributes) nonResourceAttributes() *authorizationV1.NonResourceAttributes {
+	if me.Path == "" {
+		return nil
+	}
+	return &authorizationV1.NonResourceAttributes{Path: me.Path, Verb: me.Verb}
+}
@@ -0,0 +1,292 @@
+package model
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/kore3lab/dashboard/pkg/config"
+	"github.com/kore3lab/dashboard/pkg/lang"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// compare operations
+const (
+	COMPARE_ADDED   = "added"
+	COMPARE_REMOVED = "removed"
+	COMPARE_CHANGED = "changed"
+)
+
+// server-managed metadata fields (ignored by compare, stripped by copy)
+var serverManagedMetadataFields = []string{"uid", "resourceVersion", "generation", "creationTimestamp", "deletionTimestamp", "deletionGracePeriodSeconds", "selfLink", "managedFields", "ownerReferences"}
+
+// server-managed annotations
+var serverManagedAnnotations = []string{"kubectl.kubernetes.io/last-applied-configuration", "deployment.kubernetes.io/revision"}
+
+// a resource to compare
+type CompareResource struct {
+	Cluster   string `json:"cluster"`
+	Group     string `json:"group"`
+	Version   string `json:"version"`
+	Resource  string `json:"resource"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+type CompareRequest struct {
+	Source CompareResource `json:"source"`
+	Target CompareResource `json:"target"`
+}
+
+type CompareDifference struct {
+	Path   string      `json:"path"`
+	Op     string      `json:"op"`
+	Source interface{} `json:"source,omitempty"`
+	Target interface{} `json:"target,omitempty"`
+}
+
+type CompareResult struct {
+	Source      CompareResource        `json:"source"`
+	Target      CompareResource        `json:"target"`
+	SourceData  map[string]interface{} `json:"sourceData"`
+	TargetData  map[string]interface{} `json:"targetData"`
+	Identical   bool                   `json:"identical"`
+	Differences []CompareDifference    `json:"differences"`
+}
+
+// fill target's empty fields with source's
+func (me *CompareRequest) complete() {
+	me.Source.Cluster = lang.NVL(me.Source.Cluster, config.Cluster.DefaultContext)
+	me.Target.Cluster = lang.NVL(me.Target.Cluster, me.Source.Cluster)
+	me.Target.Group = lang.NVL(me.Target.Group, me.Source.Group)
+	me.Target.Version = lang.NVL(me.Target.Version, me.Source.Version)
+	me.Target.Resource = lang.NVL(me.Target.Resource, me.Source.Resource)
+	me.Target.Namespace = lang.NVL(me.Target.Namespace, me.Source.Namespace)
+	me.Target.Name = lang.NVL(me.Target.Name, me.Source.Name)
+}
+
+// compare a resource between two clusters or namespaces
+func GetCompare(req CompareRequest) (*CompareResult, error) {
+
+	req.complete()
+	for _, r := range []CompareResource{req.Source, req.Target} {
+		if r.Version == "" || r.Resource == "" || r.Name == "" {
+			return nil, errors.NewBadRequest(fmt.Sprintf("version, resource and name are required (cluster=%s, resource=%s, name=%s)", r.Cluster, r.Resource, r.Name))
+		}
+	}
+
+	source, err := getCompareData(req.Source)
+	if err != nil {
+		return nil, err
+	}
+	target, err := getCompareData(req.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CompareResult{
+		Source:      req.Source,
+		Target:      req.Target,
+		Differences: []CompareDifference{},
+	}
+
+	if source == nil && target == nil {
+		return nil, errors.NewNotFound(schema.GroupResource{Group: req.Source.Group, Resource: req.Source.Resource}, req.Source.Name)
+	} else if source == nil {
+		result.Differences = append(result.Differences, CompareDifference{Path: "", Op: COMPARE_ADDED})
+	} else if target == nil {
+		result.Differences = append(result.Differences, CompareDifference{Path: "", Op: COMPARE_REMOVED})
+	} else {
+		result.Differences = diffValues("", source.Object, target.Object, result.Differences)
+	}
+	result.Identical = len(result.Differences) == 0
+
+	// compared with original values, respond redacted values
+	source, target = redactCompareData(source), redactCompareData(target)
+	for i, d := range result.Differences {
+		result.Differences[i].Source = redactedCompareValue(source, d.Path, d.Source)
+		result.Differences[i].Target = redactedCompareValue(target, d.Path, d.Target)
+	}
+	if source != nil {
+		result.SourceData = source.Object
+	}
+	if target != nil {
+		result.TargetData = target.Object
+	}
+
+	return result, nil
+
+}
+
+// get a normalized resource (returns nil if not exist)
+func getCompareData(r CompareResource) (*unstructured.Unstructured, error) {
+
+	client, err := config.Cluster.Client(r.Cluster)
+	if err != nil {
+		return nil, errors.NewBadRequest(err.Error())
+	}
+
+	api, err := client.NewDynamicClientSchema(r.Group, r.Version, r.Resource)
+	if err != nil {
+		return nil, err
+	}
+	api.SetNamespace(r.Namespace)
+
+	obj, err := api.GET(r.Name, metaV1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	NormalizeObject(obj, true)
+
+	return obj, nil
+
+}
+
+// a redacted copy (secret data, sensitive configmap keys)
+func redactCompareData(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	if obj == nil {
+		return nil
+	}
+	obj = obj.DeepCopy()
+	RedactObject(obj)
+	return obj
+}
+
+// a value of difference in redacted object (data, stringData, binaryData paths)
+func redactedCompareValue(obj *unstructured.Unstructured, path string, value interface{}) interface{} {
+
+	if obj == nil || value == nil || obj.GetAnnotations()[REDACTED_ANNOTATION] == "" {
+		return value
+	}
+	for _, field := range []string{"data", "stringData", "binaryData"} {
+		if !strings.HasPrefix(path, field) {
+			continue
+		}
+		data, ok := obj.Object[field].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		key := path[len(field):]
+		if key == "" {
+			return data
+		} else if strings.HasPrefix(key, ".") {
+			key = key[1:]
+		} else if strings.HasPrefix(key, "[") && strings.HasSuffix(key, "]") {
+			key = key[1 : len(key)-1]
+		} else {
+			continue
+		}
+		if v, exist := data[key]; exist {
+			return v
+		}
+	}
+	return value
+
+}
+
+// remove server-managed fields & status (if namespace is true, remove namespace too)
+func NormalizeObject(obj *unstructured.Unstructured, namespace bool) {
+
+	for _, f := range serverManagedMetadataFields {
+		unstructured.RemoveNestedField(obj.Object, "metadata", f)
+	}
+	if namespace {
+		unstructured.RemoveNestedField(obj.Object, "metadata", "namespace")
+	}
+	if annotations := obj.GetAnnotations(); annotations != nil {
+		for _, a := range serverManagedAnnotations {
+			delete(annotations, a)
+		}
+		if len(annotations) == 0 {
+			unstructured.RemoveNestedField(obj.Object, "metadata", "annotations")
+		} else {
+			obj.SetAnnotations(annotations)
+		}
+	}
+	unstructured.RemoveNestedField(obj.Object, "status")
+
+	// server allocated values
+	switch obj.GetKind() {
+	case "Service":
+		// keep "None" (headless)
+		if clusterIP, _, _ := unstructured.NestedString(obj.Object, "spec", "clusterIP"); clusterIP != coreV1.ClusterIPNone {
+			unstructured.RemoveNestedField(obj.Object, "spec", "clusterIP")
+			unstructured.RemoveNestedField(obj.Object, "spec", "clusterIPs")
+		}
+	case "PersistentVolumeClaim":
+		unstructured.RemoveNestedField(obj.Object, "spec", "volumeName")
+	case "ServiceAccount":
+		unstructured.RemoveNestedField(obj.Object, "secrets")
+	}
+
+}
+
+// append differences between a and b
+func diffValues(path string, a interface{}, b interface{}, diffs []CompareDifference) []CompareDifference {
+
+	switch va := a.(type) {
+	case map[string]interface{}:
+		if vb, ok := b.(map[string]interface{}); ok {
+			keys := []string{}
+			for k := range va {
+				keys = append(keys, k)
+			}
+			for k := range vb {
+				if _, exist := va[k]; !exist {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				p := joinPath(path, k)
+				if _, exist := vb[k]; !exist {
+					diffs = append(diffs, CompareDifference{Path: p, Op: COMPARE_REMOVED, Source: va[k]})
+				} else if _, exist := va[k]; !exist {
+					diffs = append(diffs, CompareDifference{Path: p, Op: COMPARE_ADDED, Target: vb[k]})
+				} else {
+					diffs = diffValues(p, va[k], vb[k], diffs)
+				}
+			}
+			return diffs
+		}
+	case []interface{}:
+		if vb, ok := b.([]interface{}); ok {
+			for i := 0; i < len(va) || i < len(vb); i++ {
+				p := fmt.Sprintf("%s[%d]", path, i)
+				if i >= len(vb) {
+					diffs = append(diffs, CompareDifference{Path: p, Op: COMPARE_REMOVED, Source: va[i]})
+				} else if i >= len(va) {
+					diffs = append(diffs, CompareDifference{Path: p, Op: COMPARE_ADDED, Target: vb[i]})
+				} else {
+					diffs = diffValues(p, va[i], vb[i], diffs)
+				}
+			}
+			return diffs
+		}
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		diffs = append(diffs, CompareDifference{Path: path, Op: COMPARE_CHANGED, Source: a, Target: b})
+	}
+	return diffs
+
+}
+
+func joinPath(path string, key string) string {
+	if strings.ContainsAny(key, "./") {
+		return fmt.Sprintf("%s[%s]", path, key)
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
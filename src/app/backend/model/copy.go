@@ -0,0 +1,187 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kore3lab/dashboard/pkg/config"
+	"github.com/kore3lab/dashboard/pkg/lang"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// copy results
+const (
+	COPY_CREATED = "created"
+	COPY_UPDATED = "updated"
+	COPY_FAILED  = "failed"
+)
+
+type CopyLocation struct {
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace"`
+}
+
+type CopyResource struct {
+	Group    string `json:"group"`
+	Version  string `json:"version"`
+	Resource string `json:"resource"`
+	Name     string `json:"name"`
+}
+
+type CopyRequest struct {
+	Source    CopyLocation      `json:"source"`
+	Target    CopyLocation      `json:"target"`
+	Resources []CopyResource    `json:"resources"`
+	Images    map[string]string `json:"images"` // image repository : new tag
+	DryRun    bool              `json:"dryRun"`
+}
+
+type CopyResult struct {
+	CopyResource
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Result    string `json:"result"`
+	Message   string `json:"message"`
+}
+
+// copy (promote) resources from source to target
+func CopyResources(req CopyRequest) ([]CopyResult, error) {
+
+	req.Source.Cluster = lang.NVL(req.Source.Cluster, config.Cluster.DefaultContext)
+	req.Target.Cluster = lang.NVL(req.Target.Cluster, req.Source.Cluster)
+	if req.Source.Cluster == req.Target.Cluster && lang.NVL(req.Target.Namespace, req.Source.Namespace) == req.Source.Namespace {
+		return nil, fmt.Errorf("source and target are same (cluster=%s, namespace=%s)", req.Source.Cluster, req.Source.Namespace)
+	}
+	if len(req.Resources) == 0 {
+		return nil, fmt.Errorf("resources are empty")
+	}
+
+	sourceClient, err := config.Cluster.Client(req.Source.Cluster)
+	if err != nil {
+		return nil, err
+	}
+	targetClient, err := config.Cluster.Client(req.Target.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []CopyResult{}
+	for _, r := range req.Resources {
+
+		result := CopyResult{CopyResource: r, Namespace: lang.NVL(req.Target.Namespace, req.Source.Namespace)}
+
+		// get a source object
+		api, err := sourceClient.NewDynamicClientSchema(r.Group, r.Version, r.Resource)
+		if err != nil {
+			return nil, err
+		}
+		api.SetNamespace(req.Source.Namespace)
+		obj, err := api.GET(r.Name, metaV1.GetOptions{})
+		if err != nil {
+			results = append(results, result.fail(err))
+			continue
+		}
+		result.Kind = obj.GetKind()
+
+		// strip & rewrite (server allocated values are re-allocated in target, a headless service keeps clusterIP "None")
+		NormalizeObject(obj, false)
+		if obj.GetNamespace() != "" {
+			obj.SetNamespace(result.Namespace)
+		} else {
+			result.Namespace = ""
+		}
+		if len(req.Images) > 0 {
+			rewriteImages(obj.Object, req.Images)
+		}
+
+		// exists in target
+		api, err = targetClient.NewDynamicClientSchema(r.Group, r.Version, r.Resource)
+		if err != nil {
+			return nil, err
+		}
+		api.SetNamespace(result.Namespace)
+		isUpdate := true
+		if _, err = api.GET(r.Name, metaV1.GetOptions{}); err != nil {
+			if !errors.IsNotFound(err) {
+				results = append(results, result.fail(err))
+				continue
+			}
+			isUpdate = false
+		}
+
+		// apply
+		payload, err := json.Marshal(obj.Object)
+		if err != nil {
+			results = append(results, result.fail(err))
+			continue
+		}
+		api, err = targetClient.NewDynamicClient()
+		if err != nil {
+			return nil, err
+		}
+		api.SetDryRun(req.DryRun)
+		if _, err = api.POST(bytes.NewReader(payload), isUpdate); err != nil {
+			results = append(results, result.fail(err))
+			continue
+		}
+		if isUpdate {
+			result.Result = COPY_UPDATED
+		} else {
+			result.Result = COPY_CREATED
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+
+}
+
+func (me CopyResult) fail(err error) CopyResult {
+	me.Result = COPY_FAILED
+	me.Message = err.Error()
+	return me
+}
+
+// rewrite container images tag (all "containers", "initContainers", "ephemeralContainers" in a object)
+func rewriteImages(obj map[string]interface{}, images map[string]string) {
+
+	for k, v := range obj {
+		switch val := v.(type) {
+		case map[string]interface{}:
+			rewriteImages(val, images)
+		case []interface{}:
+			isContainers := (k == "containers" || k == "initContainers" || k == "ephemeralContainers")
+			for _, e := range val {
+				if m, ok := e.(map[string]interface{}); ok {
+					if isContainers {
+						if image, ok := m["image"].(string); ok {
+							m["image"] = rewriteImageTag(image, images)
+						}
+					}
+					rewriteImages(m, images)
+				}
+			}
+		}
+	}
+
+}
+
+// "repository:tag" or "repository@digest" -> "repository:newTag"
+func rewriteImageTag(image string, images map[string]string) string {
+
+	repository := image
+	if i := strings.Index(repository, "@"); i >= 0 {
+		repository = repository[:i]
+	}
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		repository = repository[:i]
+	}
+	if tag, exist := images[repository]; exist && tag != "" {
+		return fmt.Sprintf("%s:%s", repository, tag)
+	}
+	return image
+
+}
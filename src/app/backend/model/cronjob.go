@@ -0,0 +1,225 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kore3lab/dashboard/pkg/config"
+	"github.com/kore3lab/dashboard/pkg/lang"
+	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	JOB_STATUS_ACTIVE    = "Active"
+	JOB_STATUS_SUCCEEDED = "Succeeded"
+	JOB_STATUS_FAILED    = "Failed"
+)
+
+type CronJobRunPod struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Logs   string `json:"logs"` // raw-api url of pod logs
+}
+
+type CronJobRun struct {
+	Name           string          `json:"name"`
+	Status         string          `json:"status"` // Active, Succeeded, Failed
+	Manual         bool            `json:"manual"` // triggered by "run now"
+	StartTime      *metaV1.Time    `json:"startTime"`
+	CompletionTime *metaV1.Time    `json:"completionTime"`
+	Duration       int64           `json:"duration"` // seconds (active : until now)
+	Active         int32           `json:"active"`
+	Succeeded      int32           `json:"succeeded"`
+	Failed         int32           `json:"failed"`
+	Pods           []CronJobRunPod `json:"pods"`
+}
+
+// returns pods of jobs owned by given cronjob.
+func GetCronJobPods(apiClient *kubernetes.Clientset, namespace string, name string) ([]coreV1.Pod, *coreV1.PodSpec, error) {
+
+	cronjob, jobs, err := getCronJobJobs(apiClient, namespace, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	podList, err := apiClient.CoreV1().Pods(namespace).List(context.TODO(), metaV1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	pods := []coreV1.Pod{}
+	for i := range jobs {
+		pods = append(pods, lang.FilterPodsByControllerRef(&jobs[i], podList.Items)...)
+	}
+	return pods, &cronjob.Spec.JobTemplate.Spec.Template.Spec, nil
+
+}
+
+// create a job from a cronjob template immediately ("kubectl create job --from=cronjob/<name>")
+func RunCronJob(cluster string, namespace string, name string) (*CronJobRun, error) {
+
+	clientSet, err := config.Cluster.Client(cluster)
+	if err != nil {
+		return nil, err
+	}
+	apiClient, err := clientSet.NewKubernetesClient()
+	if err != nil {
+		return nil, err
+	}
+
+	cronjob, err := apiClient.BatchV1().CronJobs(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// job name : max 63 characters (label value "job-name")
+	suffix := fmt.Sprintf("-manual-%s", strings.ToLower(lang.RandomString(5)))
+	jobName := name
+	if len(jobName)+len(suffix) > 63 {
+		jobName = jobName[:63-len(suffix)]
+	}
+	jobName = jobName + suffix
+
+	annotations := map[string]string{"cronjob.kubernetes.io/instantiate": "manual"}
+	for k, v := range cronjob.Spec.JobTemplate.Annotations {
+		annotations[k] = v
+	}
+	job := &batchV1.Job{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:            jobName,
+			Namespace:       namespace,
+			Labels:          cronjob.Spec.JobTemplate.Labels,
+			Annotations:     annotations,
+			OwnerReferences: []metaV1.OwnerReference{*metaV1.NewControllerRef(cronjob, batchV1.SchemeGroupVersion.WithKind("CronJob"))},
+		},
+		Spec: cronjob.Spec.JobTemplate.Spec,
+	}
+	if job, err = apiClient.BatchV1().Jobs(namespace).Create(context.TODO(), job, metaV1.CreateOptions{}); err != nil {
+		return nil, err
+	}
+
+	run := toCronJobRun(*job, nil, cluster)
+	return &run, nil
+
+}
+
+// suspend (true) or resume (false) a cronjob
+func SuspendCronJob(cluster string, namespace string, name string, suspend bool) (*batchV1.CronJob, error) {
+
+	clientSet, err := config.Cluster.Client(cluster)
+	if err != nil {
+		return nil, err
+	}
+	apiClient, err := clientSet.NewKubernetesClient()
+	if err != nil {
+		return nil, err
+	}
+
+	patch := []byte(fmt.Sprintf(`{"spec":{"suspend":%t}}`, suspend))
+	return apiClient.BatchV1().CronJobs(namespace).Patch(context.TODO(), name, types.MergePatchType, patch, metaV1.PatchOptions{})
+
+}
+
+// jobs owned by a cronjob (latest first) with pods
+func GetCronJobHistory(cluster string, namespace string, name string) ([]CronJobRun, error) {
+
+	clientSet, err := config.Cluster.Client(cluster)
+	if err != nil {
+		return nil, err
+	}
+	apiClient, err := clientSet.NewKubernetesClient()
+	if err != nil {
+		return nil, err
+	}
+
+	_, jobs, err := getCronJobJobs(apiClient, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	podList, err := apiClient.CoreV1().Pods(namespace).List(context.TODO(), metaV1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	runs := []CronJobRun{}
+	for i := range jobs {
+		runs = append(runs, toCronJobRun(jobs[i], lang.FilterPodsByControllerRef(&jobs[i], podList.Items), cluster))
+	}
+	return runs, nil
+
+}
+
+// a cronjob and jobs controlled by the cronjob
+func getCronJobJobs(apiClient *kubernetes.Clientset, namespace string, name string) (*batchV1.CronJob, []batchV1.Job, error) {
+
+	cronjob, err := apiClient.BatchV1().CronJobs(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	jobList, err := apiClient.BatchV1().Jobs(namespace).List(context.TODO(), metaV1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	jobs := []batchV1.Job{}
+	for _, job := range jobList.Items {
+		if metaV1.IsControlledBy(&job, cronjob) {
+			jobs = append(jobs, job)
+		}
+	}
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return jobs[j].CreationTimestamp.Before(&jobs[i].CreationTimestamp)
+	})
+	return cronjob, jobs, nil
+
+}
+
+func toCronJobRun(job batchV1.Job, pods []coreV1.Pod, cluster string) CronJobRun {
+
+	run := CronJobRun{
+		Name:           job.Name,
+		Status:         JOB_STATUS_ACTIVE,
+		Manual:         job.Annotations["cronjob.kubernetes.io/instantiate"] == "manual",
+		StartTime:      job.Status.StartTime,
+		CompletionTime: job.Status.CompletionTime,
+		Active:         job.Status.Active,
+		Succeeded:      job.Status.Succeeded,
+		Failed:         job.Status.Failed,
+		Pods:           []CronJobRunPod{},
+	}
+
+	finished := time.Now()
+	switch getJobStatus(job) {
+	case batchV1.JobComplete:
+		run.Status = JOB_STATUS_SUCCEEDED
+	case batchV1.JobFailed:
+		run.Status = JOB_STATUS_FAILED
+		for _, c := range job.Status.Conditions {
+			if c.Type == batchV1.JobFailed {
+				finished = c.LastTransitionTime.Time
+			}
+		}
+	}
+	if job.Status.CompletionTime != nil {
+		finished = job.Status.CompletionTime.Time
+	}
+	if job.Status.StartTime != nil {
+		run.Duration = int64(finished.Sub(job.Status.StartTime.Time).Seconds())
+	}
+
+	for _, pod := range pods {
+		run.Pods = append(run.Pods, CronJobRunPod{
+			Name:   pod.Name,
+			Status: lang.GetPodStatus(pod),
+			Logs:   fmt.Sprintf("/raw/clusters/%s/api/v1/namespaces/%s/pods/%s/log", cluster, pod.Namespace, pod.Name),
+		})
+	}
+	return run
+
+}
@@ -0,0 +1,320 @@
+package model
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/kore3lab/dashboard/pkg/config"
+	"github.com/kore3lab/dashboard/pkg/lang"
+	certificatesV1 "k8s.io/api/certificates/v1"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientcmdApi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	CSR_STATUS_PENDING  = "Pending"
+	CSR_STATUS_APPROVED = "Approved"
+	CSR_STATUS_DENIED   = "Denied"
+	CSR_STATUS_FAILED   = "Failed"
+	CSR_STATUS_ISSUED   = "Issued"
+
+	csrSignerName        = certificatesV1.KubeAPIServerClientSignerName
+	csrKeySize           = 2048
+	csrIssueTimeout      = 30 * time.Second
+	csrApprovalReason    = "KoreBoardApprove"
+	csrDenialReason      = "KoreBoardDeny"
+	defaultCSRExpiration = 365 * 24 * 3600
+)
+
+var csrNameInvalidChars = regexp.MustCompile("[^a-zA-Z0-9.-]")
+
+type UserCertificateRequest struct {
+	Username          string   `json:"username"`
+	Groups            []string `json:"groups"`
+	Namespace         string   `json:"namespace"`         // kubeconfig context namespace
+	ExpirationSeconds int32    `json:"expirationSeconds"` // certificate expiration (default: 1 year)
+}
+
+type UserCertificateResult struct {
+	Name       string `json:"name"` // csr name
+	Status     string `json:"status"`
+	Message    string `json:"message"`
+	Kubeconfig string `json:"kubeconfig"` // issued
+	Key        string `json:"key"`        // private key (pem), not issued yet
+}
+
+type CertificateSigningRequest struct {
+	Name              string      `json:"name"`
+	SignerName        string      `json:"signerName"`
+	Requestor         string      `json:"requestor"`
+	Username          string      `json:"username"` // requested subject (common name)
+	Groups            []string    `json:"groups"`   // requested subject (organizations)
+	Status            string      `json:"status"`
+	Message           string      `json:"message"`
+	ExpirationSeconds int32       `json:"expirationSeconds"`
+	CreationTimestamp metaV1.Time `json:"creationTimestamp"`
+}
+
+// generate a key & a csr, submit and approve (when allowed) and returns a kubeconfig if issued
+func CreateUserCertificate(cluster string, req UserCertificateRequest) (*UserCertificateResult, error) {
+
+	if req.Username == "" {
+		return nil, fmt.Errorf("username is empty")
+	}
+	if req.ExpirationSeconds == 0 {
+		req.ExpirationSeconds = defaultCSRExpiration
+	}
+
+	// private key & csr
+	key, err := rsa.GenerateKey(rand.Reader, csrKeySize)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: req.Username, Organization: req.Groups},
+	}, key)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	client, err := config.Cluster.Client(cluster)
+	if err != nil {
+		return nil, err
+	}
+	api, err := client.NewKubernetesClient()
+	if err != nil {
+		return nil, err
+	}
+
+	// submit
+	csr, err := api.CertificatesV1().CertificateSigningRequests().Create(context.TODO(), &certificatesV1.CertificateSigningRequest{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:   strings.ToLower(fmt.Sprintf("%s-%s", csrNameInvalidChars.ReplaceAllString(req.Username, "-"), lang.RandomString(5))),
+			Labels: map[string]string{MANAGED_BY_LABEL: MANAGED_BY_VALUE},
+		},
+		Spec: certificatesV1.CertificateSigningRequestSpec{
+			Request:           pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}),
+			SignerName:        csrSignerName,
+			ExpirationSeconds: &req.ExpirationSeconds,
+			Usages:            []certificatesV1.KeyUsage{certificatesV1.UsageDigitalSignature, certificatesV1.UsageKeyEncipherment, certificatesV1.UsageClientAuth},
+		},
+	}, metaV1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// once submitted, failures are returned in "message" with the key (a kubeconfig can be got later by the csr name & key)
+	result := &UserCertificateResult{Name: csr.Name, Status: CSR_STATUS_PENDING, Key: string(keyPEM)}
+
+	// approve (when the caller is allowed)
+	reviews, err := ReviewAccess(cluster, AccessReviewRequest{Attributes: []AccessAttributes{
+		{Verb: "update", Group: certificatesV1.GroupName, Resource: "certificatesigningrequests", Subresource: "approval"},
+		{Verb: "approve", Group: certificatesV1.GroupName, Resource: "signers", Name: csrSignerName},
+	}})
+	if err != nil {
+		result.Message = fmt.Sprintf("unable to review the approval access (cause=%s)", err.Error())
+		return result, nil
+	}
+	for _, r := range reviews {
+		if !r.Allowed {
+			result.Message = "not allowed to approve a certificate signing request, ask an administrator to approve it"
+			return result, nil
+		}
+	}
+	if _, err := ApproveCertificateSigningRequest(cluster, csr.Name, true, "approved on user onboarding"); err != nil {
+		result.Message = fmt.Sprintf("unable to approve a certificate signing request (cause=%s)", err.Error())
+		return result, nil
+	}
+	result.Status = CSR_STATUS_APPROVED
+
+	// wait for issued
+	var certificate []byte
+	_ = wait.PollImmediate(time.Second, csrIssueTimeout, func() (bool, error) {
+		if csr, err = api.CertificatesV1().CertificateSigningRequests().Get(context.TODO(), csr.Name, metaV1.GetOptions{}); err != nil {
+			return false, err
+		}
+		certificate = csr.Status.Certificate
+		return len(certificate) > 0, nil
+	})
+	if len(certificate) == 0 {
+		result.Message = "a certificate is not issued yet"
+		return result, nil
+	}
+
+	result.Status = CSR_STATUS_ISSUED
+	b, err := newKubeconfig(cluster, req.Username, &clientcmdApi.AuthInfo{ClientCertificateData: certificate, ClientKeyData: keyPEM}, req.Namespace)
+	if err != nil {
+		result.Message = fmt.Sprintf("unable to generate a kubeconfig (cause=%s)", err.Error())
+		return result, nil
+	}
+	result.Kubeconfig = string(b)
+	result.Key = ""
+	return result, nil
+
+}
+
+// a kubeconfig of an issued csr (private key generated on the request)
+func GetUserCertificateKubeconfig(cluster string, name string, key string, namespace string) (string, error) {
+
+	client, err := config.Cluster.Client(cluster)
+	if err != nil {
+		return "", err
+	}
+	api, err := client.NewKubernetesClient()
+	if err != nil {
+		return "", err
+	}
+	csr, err := api.CertificatesV1().CertificateSigningRequests().Get(context.TODO(), name, metaV1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	if len(csr.Status.Certificate) == 0 {
+		return "", fmt.Errorf("a certificate of '%s' is not issued yet (status=%s)", name, getCSRStatus(csr))
+	}
+	if _, err := tls.X509KeyPair(csr.Status.Certificate, []byte(key)); err != nil {
+		return "", errors.NewBadRequest(fmt.Sprintf("the key does not match the certificate of '%s' (cause=%s)", name, err.Error()))
+	}
+
+	username, _ := parseCSRSubject(csr.Spec.Request)
+	b, err := newKubeconfig(cluster, lang.NVL(username, name), &clientcmdApi.AuthInfo{ClientCertificateData: csr.Status.Certificate, ClientKeyData: []byte(key)}, namespace)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+
+}
+
+// certificate signing requests (status = "" : all)
+func GetCertificateSigningRequests(cluster string, status string) ([]CertificateSigningRequest, error) {
+
+	client, err := config.Cluster.Client(cluster)
+	if err != nil {
+		return nil, err
+	}
+	api, err := client.NewKubernetesClient()
+	if err != nil {
+		return nil, err
+	}
+	list, err := api.CertificatesV1().CertificateSigningRequests().List(context.TODO(), metaV1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	csrs := []CertificateSigningRequest{}
+	for i := range list.Items {
+		csr := toCertificateSigningRequest(&list.Items[i])
+		if status == "" || strings.EqualFold(status, csr.Status) {
+			csrs = append(csrs, csr)
+		}
+	}
+	return csrs, nil
+
+}
+
+// approve or deny a certificate signing request
+func ApproveCertificateSigningRequest(cluster string, name string, approve bool, message string) (*CertificateSigningRequest, error) {
+
+	client, err := config.Cluster.Client(cluster)
+	if err != nil {
+		return nil, err
+	}
+	api, err := client.NewKubernetesClient()
+	if err != nil {
+		return nil, err
+	}
+	csr, err := api.CertificatesV1().CertificateSigningRequests().Get(context.TODO(), name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if status := getCSRStatus(csr); status != CSR_STATUS_PENDING {
+		return nil, fmt.Errorf("'%s' is not pending (status=%s)", name, status)
+	}
+
+	condition := certificatesV1.CertificateSigningRequestCondition{
+		Type:           certificatesV1.CertificateApproved,
+		Status:         coreV1.ConditionTrue,
+		Reason:         csrApprovalReason,
+		Message:        message,
+		LastUpdateTime: metaV1.Now(),
+	}
+	if !approve {
+		condition.Type = certificatesV1.CertificateDenied
+		condition.Reason = csrDenialReason
+	}
+	csr.Status.Conditions = append(csr.Status.Conditions, condition)
+
+	if csr, err = api.CertificatesV1().CertificateSigningRequests().UpdateApproval(context.TODO(), name, csr, metaV1.UpdateOptions{}); err != nil {
+		return nil, err
+	}
+	r := toCertificateSigningRequest(csr)
+	return &r, nil
+
+}
+
+func toCertificateSigningRequest(csr *certificatesV1.CertificateSigningRequest) CertificateSigningRequest {
+
+	r := CertificateSigningRequest{
+		Name:              csr.Name,
+		SignerName:        csr.Spec.SignerName,
+		Requestor:         csr.Spec.Username,
+		Status:            getCSRStatus(csr),
+		CreationTimestamp: csr.CreationTimestamp,
+		Groups:            []string{},
+	}
+	if csr.Spec.ExpirationSeconds != nil {
+		r.ExpirationSeconds = *csr.Spec.ExpirationSeconds
+	}
+	for _, c := range csr.Status.Conditions {
+		r.Message = lang.NVL(c.Message, c.Reason)
+	}
+	r.Username, r.Groups = parseCSRSubject(csr.Spec.Request)
+	return r
+
+}
+
+// Pending, Approved, Denied, Failed, Issued
+func getCSRStatus(csr *certificatesV1.CertificateSigningRequest) string {
+
+	status := CSR_STATUS_PENDING
+	for _, c := range csr.Status.Conditions {
+		switch c.Type {
+		case certificatesV1.CertificateDenied:
+			return CSR_STATUS_DENIED
+		case certificatesV1.CertificateFailed:
+			return CSR_STATUS_FAILED
+		case certificatesV1.CertificateApproved:
+			status = CSR_STATUS_APPROVED
+		}
+	}
+	if status == CSR_STATUS_APPROVED && len(csr.Status.Certificate) > 0 {
+		status = CSR_STATUS_ISSUED
+	}
+	return status
+
+}
+
+// common name & organizations of a csr (pem)
+func parseCSRSubject(request []byte) (string, []string) {
+	block, _ := pem.Decode(request)
+	if block == nil {
+		return "", []string{}
+	}
+	r, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return "", []string{}
+	}
+	return r.Subject.CommonName, append([]string{}, r.Subject.Organization...)
+}
@@ -0,0 +1,261 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kore3lab/dashboard/pkg/config"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+type CustomResourceDefinition struct {
+	Name       string   `json:"name"`
+	Group      string   `json:"group"`
+	Version    string   `json:"version"`
+	Kind       string   `json:"kind"`
+	Resource   string   `json:"resource"`
+	Namespaced bool     `json:"namespaced"`
+	Categories []string `json:"categories"`
+}
+
+type PrinterColumn struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Format      string `json:"format"`
+	Description string `json:"description"`
+	Priority    int64  `json:"priority"`
+	JSONPath    string `json:"jsonPath"`
+}
+
+type StatusCondition struct {
+	Type               string `json:"type"`
+	Status             string `json:"status"`
+	Reason             string `json:"reason"`
+	Message            string `json:"message"`
+	LastTransitionTime string `json:"lastTransitionTime"`
+}
+
+type CustomResourceRow struct {
+	Name              string            `json:"name"`
+	Namespace         string            `json:"namespace"`
+	UID               string            `json:"uid"`
+	CreationTimestamp metaV1.Time       `json:"creationTimestamp"`
+	Cells             []interface{}     `json:"cells"`
+	Ready             string            `json:"ready"` // "True", "False", "Unknown" or "" (no ready condition)
+	Conditions        []StatusCondition `json:"conditions"`
+}
+
+type CustomResourceTable struct {
+	Definition CustomResourceDefinition `json:"definition"`
+	Columns    []PrinterColumn          `json:"columns"`
+	Rows       []CustomResourceRow      `json:"rows"`
+}
+
+// condition types that represent readiness of a resource (in order of priority)
+var readyConditionTypes = []string{"Ready", "Available", "Synced", "Healthy", "Established"}
+
+// custom resource definitions list
+func GetCustomResourceDefinitions(cluster string) ([]CustomResourceDefinition, error) {
+
+	client, err := config.Cluster.Client(cluster)
+	if err != nil {
+		return nil, err
+	}
+	api, err := client.NewDynamicClientSchema("apiextensions.k8s.io", "v1", "customresourcedefinitions")
+	if err != nil {
+		return nil, err
+	}
+	list, err := api.List(metaV1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	crds := []CustomResourceDefinition{}
+	for _, crd := range list.Items {
+		definition, _ := toCustomResourceDefinition(crd)
+		crds = append(crds, definition)
+	}
+	return crds, nil
+
+}
+
+// custom resources table (printer columns & status conditions)
+func GetCustomResourceTable(cluster string, name string, namespace string) (*CustomResourceTable, error) {
+
+	client, err := config.Cluster.Client(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	// crd
+	api, err := client.NewDynamicClientSchema("apiextensions.k8s.io", "v1", "customresourcedefinitions")
+	if err != nil {
+		return nil, err
+	}
+	crd, err := api.GET(name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	definition, version := toCustomResourceDefinition(*crd)
+	if version == nil {
+		return nil, fmt.Errorf("can't find a served version of '%s'", name)
+	}
+
+	table := &CustomResourceTable{Definition: definition, Columns: []PrinterColumn{}, Rows: []CustomResourceRow{}}
+
+	// printer columns
+	parsers := []*jsonpath.JSONPath{}
+	columns, _, _ := unstructured.NestedSlice(version, "additionalPrinterColumns")
+	for _, c := range columns {
+		m, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		col := PrinterColumn{}
+		col.Name, _, _ = unstructured.NestedString(m, "name")
+		col.Type, _, _ = unstructured.NestedString(m, "type")
+		col.Format, _, _ = unstructured.NestedString(m, "format")
+		col.Description, _, _ = unstructured.NestedString(m, "description")
+		col.Priority, _, _ = unstructured.NestedInt64(m, "priority")
+		col.JSONPath, _, _ = unstructured.NestedString(m, "jsonPath")
+
+		parser := jsonpath.New(col.Name).AllowMissingKeys(true)
+		if err := parser.Parse(relaxedJSONPath(col.JSONPath)); err != nil {
+			return nil, fmt.Errorf("invalid printer column '%s' (jsonPath=%s, cause=%s)", col.Name, col.JSONPath, err.Error())
+		}
+		parsers = append(parsers, parser)
+		table.Columns = append(table.Columns, col)
+	}
+
+	// custom resources
+	api, err = client.NewDynamicClientSchema(definition.Group, definition.Version, definition.Resource)
+	if err != nil {
+		return nil, err
+	}
+	if definition.Namespaced {
+		api.SetNamespace(namespace)
+	}
+	list, err := api.List(metaV1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range list.Items {
+		row := CustomResourceRow{
+			Name:              item.GetName(),
+			Namespace:         item.GetNamespace(),
+			UID:               string(item.GetUID()),
+			CreationTimestamp: item.GetCreationTimestamp(),
+			Cells:             []interface{}{},
+			Conditions:        GetStatusConditions(item.Object),
+		}
+		for _, parser := range parsers {
+			row.Cells = append(row.Cells, findJSONPathValue(parser, item.Object))
+		}
+		row.Ready = getReadyStatus(row.Conditions)
+		table.Rows = append(table.Rows, row)
+	}
+
+	return table, nil
+
+}
+
+// returns a definition and a served version (storage version first)
+func toCustomResourceDefinition(crd unstructured.Unstructured) (CustomResourceDefinition, map[string]interface{}) {
+
+	definition := CustomResourceDefinition{Name: crd.GetName()}
+	definition.Group, _, _ = unstructured.NestedString(crd.Object, "spec", "group")
+	definition.Kind, _, _ = unstructured.NestedString(crd.Object, "spec", "names", "kind")
+	definition.Resource, _, _ = unstructured.NestedString(crd.Object, "spec", "names", "plural")
+	definition.Categories, _, _ = unstructured.NestedStringSlice(crd.Object, "spec", "names", "categories")
+	scope, _, _ := unstructured.NestedString(crd.Object, "spec", "scope")
+	definition.Namespaced = (scope == "Namespaced")
+
+	var served map[string]interface{}
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, v := range versions {
+		if m, ok := v.(map[string]interface{}); ok {
+			if b, _, _ := unstructured.NestedBool(m, "served"); !b {
+				continue
+			}
+			if b, _, _ := unstructured.NestedBool(m, "storage"); b || served == nil {
+				served = m
+			}
+		}
+	}
+	if served != nil {
+		definition.Version, _, _ = unstructured.NestedString(served, "name")
+	}
+
+	return definition, served
+
+}
+
+// normalized "status.conditions"
+func GetStatusConditions(obj map[string]interface{}) []StatusCondition {
+
+	conditions := []StatusCondition{}
+	list, _, _ := unstructured.NestedSlice(obj, "status", "conditions")
+	for _, c := range list {
+		if m, ok := c.(map[string]interface{}); ok {
+			cond := StatusCondition{}
+			cond.Type, _, _ = unstructured.NestedString(m, "type")
+			cond.Status, _, _ = unstructured.NestedString(m, "status")
+			cond.Reason, _, _ = unstructured.NestedString(m, "reason")
+			cond.Message, _, _ = unstructured.NestedString(m, "message")
+			cond.LastTransitionTime, _, _ = unstructured.NestedString(m, "lastTransitionTime")
+			if cond.Type != "" {
+				conditions = append(conditions, cond)
+			}
+		}
+	}
+	return conditions
+
+}
+
+func getReadyStatus(conditions []StatusCondition) string {
+	for _, t := range readyConditionTypes {
+		for _, c := range conditions {
+			if c.Type == t {
+				return c.Status
+			}
+		}
+	}
+	return ""
+}
+
+// ".status.replicas" -> "{.status.replicas}"
+func relaxedJSONPath(path string) string {
+	path = strings.TrimSpace(path)
+	if strings.HasPrefix(path, "{") {
+		return path
+	}
+	if !strings.HasPrefix(path, ".") {
+		path = "." + path
+	}
+	return fmt.Sprintf("{%s}", path)
+}
+
+// evaluate a json-path (returns a value or values)
+func findJSONPathValue(parser *jsonpath.JSONPath, obj map[string]interface{}) interface{} {
+
+	results, err := parser.FindResults(obj)
+	if err != nil || len(results) == 0 {
+		return nil
+	}
+	values := []interface{}{}
+	for _, r := range results[0] {
+		if r.IsValid() && r.CanInterface() {
+			values = append(values, r.Interface())
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	} else if len(values) == 1 {
+		return values[0]
+	}
+	return values
+
+}
@@ -34,16 +34,16 @@ func GetDaemonSetPods(apiClient *kubernetes.Clientset, namespace string, name st
 
 }
 
-// daemonset's available-ready count in a cluster
-func GetDaemonSetsReady(apiClient *kubernetes.Clientset, options metaV1.ListOptions) (available int, ready int, err error) {
+// daemonset's available-ready count in a cluster (namespace = "" : all namespaces)
+func GetDaemonSetsReady(apiClient *kubernetes.Clientset, namespace string, options metaV1.ListOptions) (available int, ready int, err error) {
 
-	list, err := apiClient.AppsV1().DaemonSets("").List(context.TODO(), options)
+	list, err := apiClient.AppsV1().DaemonSets(namespace).List(context.TODO(), options)
 	if err != nil {
 		return available, ready, err
 	}
 	available = len(list.Items)
 	for _, m := range list.Items {
-		if m.Status.NumberAvailable == m.Status.NumberAvailable {
+		if m.Status.NumberReady >= m.Status.DesiredNumberScheduled && m.Status.NumberAvailable >= m.Status.DesiredNumberScheduled {
 			ready += 1
 		}
 	}
@@ -0,0 +1,197 @@
+package model
+
+import (
+	"context"
+	"sort"
+
+	"github.com/kore3lab/dashboard/pkg/config"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+const (
+	dashboardTimeoutSeconds = int64(5)
+	dashboardEventsLimit    = 20
+)
+
+type WorkloadSummary struct {
+	Ready     int `json:"ready"`
+	Available int `json:"available"` // total count
+}
+
+type JobSummary struct {
+	Total     int `json:"total"`
+	Active    int `json:"active"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+}
+
+type CronJobSummary struct {
+	Total     int `json:"total"`
+	Active    int `json:"active"` // cronjobs with running jobs
+	Suspended int `json:"suspended"`
+}
+
+type NamespaceSummary struct {
+	Total       int `json:"total"`
+	Active      int `json:"active"`
+	Terminating int `json:"terminating"`
+}
+
+type DashboardEvent struct {
+	Namespace     string      `json:"namespace"`
+	Kind          string      `json:"kind"`
+	Name          string      `json:"name"`
+	Reason        string      `json:"reason"`
+	Message       string      `json:"message"`
+	Count         int32       `json:"count"`
+	LastTimestamp metaV1.Time `json:"lastTimestamp"`
+}
+
+type DashboardSummary struct {
+	DaemonSet             WorkloadSummary   `json:"daemonset"`
+	Deployment            WorkloadSummary   `json:"deployment"`
+	ReplicaSet            WorkloadSummary   `json:"replicaset"`
+	StatefulSet           WorkloadSummary   `json:"statefulset"`
+	Pod                   WorkloadSummary   `json:"pod"`
+	Job                   JobSummary        `json:"job"`
+	CronJob               CronJobSummary    `json:"cronjob"`
+	PersistentVolumeClaim map[string]int    `json:"persistentvolumeclaim"` // by phase (Bound, Pending, Lost)
+	Service               map[string]int    `json:"service"`               // by type (ClusterIP, NodePort, LoadBalancer, ExternalName)
+	Namespace             NamespaceSummary  `json:"namespace"`
+	Events                []DashboardEvent  `json:"events"` // recent warning events
+	Errors                map[string]string `json:"errors"` // partial failures (by summary key)
+}
+
+// summary of a cluster or a namespace (namespace = "" : all namespaces), failures of each summary are reported in "errors"
+func GetDashboardSummary(cluster string, namespace string) (*DashboardSummary, error) {
+
+	clientSet, err := config.Cluster.Client(cluster)
+	if err != nil {
+		return nil, err
+	}
+	apiClient, err := clientSet.NewKubernetesClient()
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := dashboardTimeoutSeconds
+	options := metaV1.ListOptions{TimeoutSeconds: &timeout}
+
+	summary := &DashboardSummary{
+		PersistentVolumeClaim: map[string]int{},
+		Service:               map[string]int{},
+		Events:                []DashboardEvent{},
+		Errors:                map[string]string{},
+	}
+	report := func(key string, err error) {
+		if err != nil {
+			summary.Errors[key] = err.Error()
+		}
+	}
+
+	// workloads
+	summary.DaemonSet, err = newWorkloadSummary(GetDaemonSetsReady(apiClient, namespace, options))
+	report("daemonset", err)
+	summary.Deployment, err = newWorkloadSummary(GetDeploymentsReady(apiClient, namespace, options))
+	report("deployment", err)
+	summary.ReplicaSet, err = newWorkloadSummary(GetReplicaSetsReady(apiClient, namespace, options))
+	report("replicaset", err)
+	summary.StatefulSet, err = newWorkloadSummary(GetStatefulSetsReady(apiClient, namespace, options))
+	report("statefulset", err)
+	summary.Pod, err = newWorkloadSummary(GetPodsReady(apiClient, namespace, options))
+	report("pod", err)
+
+	// jobs & cronjobs
+	summary.Job, err = GetJobsSummary(apiClient, namespace, options)
+	report("job", err)
+	summary.CronJob, err = GetCronJobsSummary(apiClient, namespace, options)
+	report("cronjob", err)
+
+	// persistentvolumeclaims
+	if pvcs, err := apiClient.CoreV1().PersistentVolumeClaims(namespace).List(context.TODO(), options); err != nil {
+		report("persistentvolumeclaim", err)
+	} else {
+		for _, m := range pvcs.Items {
+			summary.PersistentVolumeClaim[string(m.Status.Phase)] += 1
+		}
+	}
+
+	// services
+	if services, err := apiClient.CoreV1().Services(namespace).List(context.TODO(), options); err != nil {
+		report("service", err)
+	} else {
+		for _, m := range services.Items {
+			summary.Service[string(m.Spec.Type)] += 1
+		}
+	}
+
+	// namespaces
+	if namespace == "" {
+		if namespaces, err := apiClient.CoreV1().Namespaces().List(context.TODO(), options); err != nil {
+			report("namespace", err)
+		} else {
+			for _, m := range namespaces.Items {
+				summary.Namespace.add(m)
+			}
+		}
+	} else {
+		if m, err := apiClient.CoreV1().Namespaces().Get(context.TODO(), namespace, metaV1.GetOptions{}); err != nil {
+			report("namespace", err)
+		} else {
+			summary.Namespace.add(*m)
+		}
+	}
+
+	// warning events
+	eventOptions := options
+	eventOptions.FieldSelector = fields.OneTermEqualSelector("type", coreV1.EventTypeWarning).String()
+	if events, err := apiClient.CoreV1().Events(namespace).List(context.TODO(), eventOptions); err != nil {
+		report("events", err)
+	} else {
+		for _, m := range events.Items {
+			summary.Events = append(summary.Events, DashboardEvent{
+				Namespace:     m.InvolvedObject.Namespace,
+				Kind:          m.InvolvedObject.Kind,
+				Name:          m.InvolvedObject.Name,
+				Reason:        m.Reason,
+				Message:       m.Message,
+				Count:         m.Count,
+				LastTimestamp: eventTimestamp(m),
+			})
+		}
+		sort.SliceStable(summary.Events, func(i, j int) bool {
+			return summary.Events[j].LastTimestamp.Before(&summary.Events[i].LastTimestamp)
+		})
+		if len(summary.Events) > dashboardEventsLimit {
+			summary.Events = summary.Events[:dashboardEventsLimit]
+		}
+	}
+
+	return summary, nil
+
+}
+
+func newWorkloadSummary(available int, ready int, err error) (WorkloadSummary, error) {
+	return WorkloadSummary{Available: available, Ready: ready}, err
+}
+
+func (me *NamespaceSummary) add(ns coreV1.Namespace) {
+	me.Total += 1
+	if ns.Status.Phase == coreV1.NamespaceTerminating {
+		me.Terminating += 1
+	} else {
+		me.Active += 1
+	}
+}
+
+// last timestamp of an event (events.k8s.io events have eventTime only)
+func eventTimestamp(ev coreV1.Event) metaV1.Time {
+	if !ev.LastTimestamp.IsZero() {
+		return ev.LastTimestamp
+	} else if !ev.EventTime.IsZero() {
+		return metaV1.NewTime(ev.EventTime.Time)
+	}
+	return ev.FirstTimestamp
+}
@@ -0,0 +1,134 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kore3lab/dashboard/pkg/config"
+	"github.com/kore3lab/dashboard/pkg/lang"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	DEBUG_TERMTYPE           = "debug"
+	defaultDebugImage        = "busybox:stable"
+	debugContainerRunTimeout = 60 * time.Second
+)
+
+type DebugContainerRequest struct {
+	Name            string   `json:"name"`            // default: "debugger-xxxxx"
+	Image           string   `json:"image"`           // default: "busybox:stable"
+	TargetContainer string   `json:"targetContainer"` // share process namespace with a container
+	Command         []string `json:"command"`         // default: image entrypoint
+}
+
+type DebugContainer struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	Image     string `json:"image"`
+	Status    string `json:"status"` // Waiting, Running, Terminated
+	Message   string `json:"message"`
+	Terminal  string `json:"terminal"` // terminal service url (termtype "debug")
+}
+
+// inject an ephemeral debug container into a running pod (ephemeralcontainers subresource)
+func CreateDebugContainer(cluster string, namespace string, name string, req DebugContainerRequest) (*DebugContainer, error) {
+
+	client, err := config.Cluster.Client(cluster)
+	if err != nil {
+		return nil, err
+	}
+	api, err := client.NewKubernetesClient()
+	if err != nil {
+		return nil, err
+	}
+
+	pod, err := api.CoreV1().Pods(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if pod.Status.Phase != coreV1.PodRunning {
+		return nil, fmt.Errorf("pod '%s' is not running (phase=%s)", name, pod.Status.Phase)
+	}
+
+	// validate a target container & a container name
+	if req.TargetContainer != "" {
+		found := false
+		for _, c := range pod.Spec.Containers {
+			if c.Name == req.TargetContainer {
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("target container '%s' not found in pod '%s'", req.TargetContainer, name)
+		}
+	}
+	req.Name = lang.NVL(req.Name, fmt.Sprintf("debugger-%s", strings.ToLower(lang.RandomString(5))))
+	for _, c := range pod.Spec.Containers {
+		if c.Name == req.Name {
+			return nil, fmt.Errorf("container '%s' already exists in pod '%s'", req.Name, name)
+		}
+	}
+	for _, c := range pod.Spec.EphemeralContainers {
+		if c.Name == req.Name {
+			return nil, fmt.Errorf("container '%s' already exists in pod '%s'", req.Name, name)
+		}
+	}
+
+	// ephemeralcontainers subresource
+	pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers, coreV1.EphemeralContainer{
+		EphemeralContainerCommon: coreV1.EphemeralContainerCommon{
+			Name:                     req.Name,
+			Image:                    lang.NVL(req.Image, defaultDebugImage),
+			Command:                  req.Command,
+			ImagePullPolicy:          coreV1.PullIfNotPresent,
+			Stdin:                    true,
+			TTY:                      true,
+			TerminationMessagePolicy: coreV1.TerminationMessageReadFile,
+		},
+		TargetContainerName: req.TargetContainer,
+	})
+	if pod, err = api.CoreV1().Pods(namespace).UpdateEphemeralContainers(context.TODO(), name, pod, metaV1.UpdateOptions{}); err != nil {
+		return nil, err
+	}
+
+	debug := &DebugContainer{
+		Namespace: namespace,
+		Pod:       name,
+		Container: req.Name,
+		Image:     lang.NVL(req.Image, defaultDebugImage),
+		Status:    "Waiting",
+		Terminal:  fmt.Sprintf("/api/terminal/clusters/%s/namespaces/%s/pods/%s/containers/%s/termtype/%s", cluster, namespace, name, req.Name, DEBUG_TERMTYPE),
+	}
+
+	// wait for running
+	_ = wait.PollImmediate(time.Second, debugContainerRunTimeout, func() (bool, error) {
+		p, err := api.CoreV1().Pods(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, s := range p.Status.EphemeralContainerStatuses {
+			if s.Name != req.Name {
+				continue
+			}
+			if s.State.Running != nil {
+				debug.Status, debug.Message = "Running", ""
+				return true, nil
+			} else if s.State.Terminated != nil {
+				debug.Status, debug.Message = "Terminated", lang.NVL(s.State.Terminated.Message, s.State.Terminated.Reason)
+				return true, nil
+			} else if s.State.Waiting != nil {
+				debug.Message = lang.NVL(s.State.Waiting.Message, s.State.Waiting.Reason)
+			}
+		}
+		return false, nil
+	})
+
+	return debug, nil
+
+}
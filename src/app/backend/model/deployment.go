@@ -40,19 +40,28 @@ func GetDeploymentPods(apiClient *kubernetes.Clientset, namespace string, name s
 
 }
 
-// deployment's available-ready count in a cluster
-func GetDeploymentsReady(apiClient *kubernetes.Clientset, options metaV1.ListOptions) (available int, ready int, err error) {
+// deployment's available-ready count in a cluster (namespace = "" : all namespaces)
+func GetDeploymentsReady(apiClient *kubernetes.Clientset, namespace string, options metaV1.ListOptions) (available int, ready int, err error) {
 
-	list, err := apiClient.AppsV1().Deployments("").List(context.TODO(), options)
+	list, err := apiClient.AppsV1().Deployments(namespace).List(context.TODO(), options)
 	if err != nil {
 		return available, ready, err
 	}
 	available = len(list.Items)
 	for _, m := range list.Items {
-		if m.Status.AvailableReplicas == m.Status.ReadyReplicas {
+		// rollout completed & all desired replicas available
+		if m.Status.ObservedGeneration >= m.Generation && m.Status.UpdatedReplicas >= desiredReplicas(m.Spec.Replicas) && m.Status.AvailableReplicas >= desiredReplicas(m.Spec.Replicas) {
 			ready += 1
 		}
 	}
 	return available, ready, err
 
 }
+
+// desired replicas (default: 1)
+func desiredReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
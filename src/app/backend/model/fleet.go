@@ -0,0 +1,203 @@
+package model
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kore3lab/dashboard/pkg/config"
+	"github.com/kore3lab/dashboard/pkg/lang"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/metrics/pkg/client/clientset/versioned"
+)
+
+const (
+	FLEET_STATUS_HEALTHY     = "Healthy"
+	FLEET_STATUS_DEGRADED    = "Degraded"
+	FLEET_STATUS_UNREACHABLE = "Unreachable"
+
+	fleetClusterTimeout = 10 * time.Second // per cluster
+	fleetCacheTTL       = 30 * time.Second
+)
+
+type PodHealthSummary struct {
+	Total   int `json:"total"`
+	OK      int `json:"ok"`
+	Warning int `json:"warning"`
+	Error   int `json:"error"`
+}
+
+type ClusterHealth struct {
+	Name        string           `json:"name"`
+	Status      string           `json:"status"` // Healthy, Degraded, Unreachable
+	Reachable   bool             `json:"reachable"`
+	Version     string           `json:"version"`
+	Nodes       WorkloadSummary  `json:"nodes"`
+	Pods        PodHealthSummary `json:"pods"`
+	Utilization struct {
+		CPU         *float32 `json:"cpu,omitempty"`         // percent of allocatable (unset : unavailable)
+		Memory      *float32 `json:"memory,omitempty"`      // percent of allocatable (unset : unavailable)
+		Unavailable string   `json:"unavailable,omitempty"` // cause of unavailable utilization (eg. metrics-server is not installed)
+	} `json:"utilization"`
+	Errors  []string `json:"errors"`
+	Elapsed int64    `json:"elapsed"` // milliseconds
+}
+
+type Fleet struct {
+	Clusters []ClusterHealth `json:"clusters"`
+	Summary  map[string]int  `json:"summary"` // clusters by status
+	Updated  time.Time       `json:"updated"`
+}
+
+// fleet cache (a refresh is shared by concurrent requests)
+var fleetCache = struct {
+	fleet   *Fleet
+	expired time.Time
+	mu      sync.Mutex
+}{}
+
+// health summary of all contexts (cached), refresh = true : ignore a cache
+func GetFleet(refresh bool) *Fleet {
+
+	fleetCache.mu.Lock()
+	defer fleetCache.mu.Unlock()
+
+	if refresh || fleetCache.fleet == nil || time.Now().After(fleetCache.expired) {
+		fleetCache.fleet = newFleet(config.Cluster.ClusterNames)
+		fleetCache.expired = time.Now().Add(fleetCacheTTL)
+	}
+	return fleetCache.fleet
+
+}
+
+// collect health summaries of clusters concurrently
+func newFleet(clusters []string) *Fleet {
+
+	fleet := &Fleet{
+		Clusters: make([]ClusterHealth, len(clusters)),
+		Summary:  map[string]int{FLEET_STATUS_HEALTHY: 0, FLEET_STATUS_DEGRADED: 0, FLEET_STATUS_UNREACHABLE: 0},
+		Updated:  time.Now(),
+	}
+
+	var wg sync.WaitGroup
+	for i, name := range clusters {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), fleetClusterTimeout)
+			defer cancel()
+
+			ch := make(chan ClusterHealth, 1)
+			go func() { ch <- getClusterHealth(ctx, name) }()
+			select {
+			case fleet.Clusters[i] = <-ch:
+			case <-ctx.Done():
+				fleet.Clusters[i] = ClusterHealth{Name: name, Status: FLEET_STATUS_UNREACHABLE, Errors: []string{ctx.Err().Error()}, Elapsed: fleetClusterTimeout.Milliseconds()}
+			}
+		}(i, name)
+	}
+	wg.Wait()
+
+	for _, c := range fleet.Clusters {
+		fleet.Summary[c.Status] += 1
+	}
+	return fleet
+
+}
+
+// reachability, version, nodes, pods and utilization of a cluster
+func getClusterHealth(ctx context.Context, name string) ClusterHealth {
+
+	started := time.Now()
+	health := ClusterHealth{Name: name, Status: FLEET_STATUS_UNREACHABLE, Errors: []string{}}
+
+	fail := func(err error) ClusterHealth {
+		health.Errors = append(health.Errors, err.Error())
+		health.Elapsed = time.Since(started).Milliseconds()
+		return health
+	}
+
+	clientSet, err := config.Cluster.Client(name)
+	if err != nil {
+		return fail(err)
+	}
+	restConfig := rest.CopyConfig(clientSet.RESTConfig)
+	restConfig.Timeout = fleetClusterTimeout
+	apiClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return fail(err)
+	}
+
+	// reachability & version
+	version, err := apiClient.Discovery().ServerVersion()
+	if err != nil {
+		return fail(err)
+	}
+	health.Reachable = true
+	health.Status = FLEET_STATUS_HEALTHY
+	health.Version = version.GitVersion
+
+	// nodes
+	allocatable := MetricUnit{}
+	if nodes, err := apiClient.CoreV1().Nodes().List(ctx, metaV1.ListOptions{}); err != nil {
+		health.Errors = append(health.Errors, err.Error())
+	} else {
+		health.Nodes.Available = len(nodes.Items)
+		for _, m := range nodes.Items {
+			if findNodeStatus(m) == "Ready" {
+				health.Nodes.Ready += 1
+			}
+			allocatable.CPU += m.Status.Allocatable.Cpu().MilliValue()
+			allocatable.Memory += m.Status.Allocatable.Memory().Value()
+		}
+	}
+
+	// pods
+	if pods, err := apiClient.CoreV1().Pods("").List(ctx, metaV1.ListOptions{}); err != nil {
+		health.Errors = append(health.Errors, err.Error())
+	} else {
+		health.Pods.Total = len(pods.Items)
+		for _, m := range pods.Items {
+			switch severity, _, _ := lang.GetPodHealth(m); severity {
+			case lang.HEALTH_OK:
+				health.Pods.OK += 1
+			case lang.HEALTH_WARNING:
+				health.Pods.Warning += 1
+			default:
+				health.Pods.Error += 1
+			}
+		}
+	}
+
+	// utilization (metrics-server is optional, not a health error)
+	if metricsClient, err := versioned.NewForConfig(restConfig); err != nil {
+		health.Utilization.Unavailable = err.Error()
+	} else if metrics, err := metricsClient.MetricsV1beta1().NodeMetricses().List(ctx, metaV1.ListOptions{}); err != nil {
+		health.Utilization.Unavailable = err.Error()
+	} else {
+		usage := MetricUnit{}
+		for _, m := range metrics.Items {
+			usage.CPU += m.Usage.Cpu().MilliValue()
+			usage.Memory += m.Usage.Memory().Value()
+		}
+		if allocatable.CPU > 0 {
+			cpu := float32(lang.DivideRound(usage.CPU, allocatable.CPU, 4) * 100)
+			health.Utilization.CPU = &cpu
+		}
+		if allocatable.Memory > 0 {
+			memory := float32(lang.DivideRound(usage.Memory, allocatable.Memory, 4) * 100)
+			health.Utilization.Memory = &memory
+		}
+	}
+
+	// api, node or pod failures
+	if len(health.Errors) > 0 || health.Nodes.Ready < health.Nodes.Available {
+		health.Status = FLEET_STATUS_DEGRADED
+	}
+	health.Elapsed = time.Since(started).Milliseconds()
+	return health
+
+}
@@ -3,17 +3,25 @@ package model
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/kore3lab/dashboard/pkg/config"
 	"github.com/kore3lab/dashboard/pkg/lang"
+	log "github.com/sirupsen/logrus"
+	batchV1 "k8s.io/api/batch/v1"
 	coreV1 "k8s.io/api/core/v1"
 	networkV1 "k8s.io/api/networking/v1"
+	rbacV1 "k8s.io/api/rbac/v1"
+	storageV1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
-// topoplogy graph
-func GetTopologyGraph(cluster string, namespace string) (topology Topology, err error) {
+// topoplogy graph (withMetrics : usage, utilization, health of pods and nodes)
+func GetTopologyGraph(cluster string, namespace string, withMetrics bool) (topology Topology, err error) {
 
 	topology = Topology{Nodes: []topologyNode{}, Links: []topologyLink{}}
 
@@ -118,10 +126,101 @@ func GetTopologyGraph(cluster string, namespace string) (topology Topology, err
 
 	}
 
+	if withMetrics {
+		addTopologyMetrics(client, namespace, topology, podList.Items, nodeList.Items)
+	}
+
 	return
 
 }
 
+// attach metrics (metrics-server) to pods and nodes, metrics-server failures are ignored (health, restarts and conditions only)
+func addTopologyMetrics(client *config.ClientSet, namespace string, topology Topology, pods []coreV1.Pod, nodes []coreV1.Node) {
+
+	podUsages := map[string]MetricUnit{}
+	nodeUsages := map[string]MetricUnit{}
+	if metricsClient, err := client.NewMetricsClient(); err != nil {
+		log.Warnf("Unable to create a metrics client (cause=%v)", err)
+	} else {
+		if metrics, err := metricsClient.MetricsV1beta1().PodMetricses(namespace).List(context.TODO(), v1.ListOptions{}); err != nil {
+			log.Warnf("Unable to get pod metrics (cause=%v)", err)
+		} else {
+			for _, m := range metrics.Items {
+				usage := MetricUnit{}
+				for _, c := range m.Containers {
+					usage.CPU += c.Usage.Cpu().MilliValue()
+					usage.Memory += c.Usage.Memory().Value()
+				}
+				podUsages[m.Namespace+"/"+m.Name] = usage
+			}
+		}
+		if metrics, err := metricsClient.MetricsV1beta1().NodeMetricses().List(context.TODO(), v1.ListOptions{}); err != nil {
+			log.Warnf("Unable to get node metrics (cause=%v)", err)
+		} else {
+			for _, m := range metrics.Items {
+				nodeUsages[m.Name] = MetricUnit{CPU: m.Usage.Cpu().MilliValue(), Memory: m.Usage.Memory().Value()}
+			}
+		}
+	}
+
+	// usage & utilization are unset if there is no sample (or no capacity)
+	newMetrics := func(usage MetricUnit, sampled bool, capacity MetricUnit) *TopologyMetrics {
+		m := &TopologyMetrics{Capacity: capacity}
+		if !sampled {
+			return m
+		}
+		m.Usage = &usage
+		if capacity.CPU > 0 {
+			cpu := lang.DivideRound(usage.CPU, capacity.CPU, 4)
+			m.Utilization.CPU = &cpu
+		}
+		if capacity.Memory > 0 {
+			memory := lang.DivideRound(usage.Memory, capacity.Memory, 4)
+			m.Utilization.Memory = &memory
+		}
+		return m
+	}
+
+	metrics := map[string]*TopologyMetrics{} // by node id
+	for _, pod := range pods {
+		requests := MetricUnit{}
+		for _, c := range pod.Spec.Containers {
+			requests.CPU += c.Resources.Requests.Cpu().MilliValue()
+			requests.Memory += c.Resources.Requests.Memory().Value()
+		}
+		usage, sampled := podUsages[pod.Namespace+"/"+pod.Name]
+		m := newMetrics(usage, sampled, requests)
+		m.Health, _, _ = lang.GetPodHealth(pod)
+		for _, s := range pod.Status.ContainerStatuses {
+			m.Restarts += s.RestartCount
+		}
+		metrics[string(pod.UID)] = m
+	}
+	for _, node := range nodes {
+		usage, sampled := nodeUsages[node.Name]
+		m := newMetrics(usage, sampled, MetricUnit{CPU: node.Status.Allocatable.Cpu().MilliValue(), Memory: node.Status.Allocatable.Memory().Value()})
+		m.Health = lang.HEALTH_OK
+		m.Conditions = map[string]string{}
+		for _, c := range node.Status.Conditions {
+			m.Conditions[string(c.Type)] = string(c.Status)
+			if (c.Type == coreV1.NodeReady) != (c.Status == coreV1.ConditionTrue) {
+				m.Health = lang.HEALTH_WARNING // not ready or pressure conditions
+			}
+		}
+		if m.Conditions[string(coreV1.NodeReady)] != string(coreV1.ConditionTrue) {
+			m.Health = lang.HEALTH_ERROR
+		}
+		metrics[node.Name] = m
+	}
+
+	for i, n := range topology.Nodes {
+		if n.Kind == ELEMENT_KIND_POD || n.Kind == ELEMENT_KIND_NODE {
+			topology.Nodes[i].Metrics = metrics[n.Id]
+		}
+	}
+
+}
+
 // get group versions
 func getGroupVersion(client *config.ClientSet) (coreVersion string, appsVersion string, networkVersion string, err error) {
 	if discoveryClient, err := client.NewDiscoveryClient(); err == nil {
@@ -131,7 +230,7 @@ func getGroupVersion(client *config.ClientSet) (coreVersion string, appsVersion
 					coreVersion = g.PreferredVersion.GroupVersion
 				} else if g.Name == "apps" {
 					appsVersion = g.PreferredVersion.GroupVersion
-				} else if g.Name == "network" {
+				} else if g.Name == "networking.k8s.io" {
 					networkVersion = g.PreferredVersion.GroupVersion
 				}
 			}
@@ -147,13 +246,79 @@ func newHierarchyNode(ty v1.TypeMeta, obj v1.ObjectMeta, owner string) Hierarchy
 	}
 	if len(owner) > 0 {
 		h.Owner = owner
-	} else if len(obj.OwnerReferences) > 0 {
-		h.Owner = string(obj.OwnerReferences[0].UID)
+	} else if ref := ownerReferenceOf(obj.OwnerReferences); ref != nil {
+		h.Owner = string(ref.UID)
 	}
 
 	return h
 }
 
+// a controller reference or the first owner reference
+func ownerReferenceOf(refs []v1.OwnerReference) *v1.OwnerReference {
+	for i := range refs {
+		if refs[i].Controller != nil && *refs[i].Controller {
+			return &refs[i]
+		}
+	}
+	if len(refs) > 0 {
+		return &refs[0]
+	}
+	return nil
+}
+
+const ownerChainLimit = 10 // max depth of owner references (guards against cyclic references)
+
+// resolves owner references of any kinds using a discovery and a dynamic client
+type ownerResolver struct {
+	client    *config.ClientSet
+	resources map[string]*v1.APIResource // by "apiVersion/kind"
+}
+
+func newOwnerResolver(client *config.ClientSet) *ownerResolver {
+	return &ownerResolver{client: client, resources: map[string]*v1.APIResource{}}
+}
+
+// get an owner object (namespace : a namespace of an owned object)
+func (me *ownerResolver) get(namespace string, ref v1.OwnerReference) (*unstructured.Unstructured, error) {
+
+	key := ref.APIVersion + "/" + ref.Kind
+	if _, ok := me.resources[key]; !ok {
+		discoveryClient, err := me.client.NewDiscoveryClient()
+		if err != nil {
+			return nil, err
+		}
+		list, err := discoveryClient.ServerResourcesForGroupVersion(ref.APIVersion)
+		if err != nil {
+			return nil, err
+		}
+		me.resources[key] = nil
+		for i, r := range list.APIResources {
+			if r.Kind == ref.Kind && !strings.Contains(r.Name, "/") {
+				me.resources[key] = &list.APIResources[i]
+				break
+			}
+		}
+	}
+	resource := me.resources[key]
+	if resource == nil {
+		return nil, fmt.Errorf("unable to find a resource of '%s'", key)
+	}
+
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return nil, err
+	}
+	api, err := me.client.NewDynamicClientSchema(gv.Group, gv.Version, resource.Name)
+	if err != nil {
+		return nil, err
+	}
+	if resource.Namespaced {
+		api.SetNamespace(namespace)
+	}
+	return api.GET(ref.Name, v1.GetOptions{})
+
+}
+
 // workload graph
 func GetWorkloadGraph(cluster string, namespace string) (Hierarchy, error) {
 
@@ -189,12 +354,19 @@ func GetWorkloadGraph(cluster string, namespace string) (Hierarchy, error) {
 		hierarchy[namespace] = []HierarchyNode{}
 	}
 
+	owners := []v1.ObjectMeta{} // objects to follow owner references
+	add := func(kind string, apiVersion string, obj v1.ObjectMeta) {
+		hierarchy[obj.Namespace] = append(hierarchy[obj.Namespace], newHierarchyNode(v1.TypeMeta{APIVersion: apiVersion, Kind: kind}, obj, ""))
+		owners = append(owners, obj)
+	}
+	batchVersion := batchV1.SchemeGroupVersion.String()
+
 	//deployment
 	if deployments, err := api.AppsV1().Deployments(namespace).List(context.TODO(), v1.ListOptions{}); err != nil {
 		return nil, err
 	} else {
 		for _, deploy := range deployments.Items {
-			hierarchy[deploy.Namespace] = append(hierarchy[deploy.Namespace], newHierarchyNode(v1.TypeMeta{APIVersion: appsVersion, Kind: "Deployment"}, deploy.ObjectMeta, ""))
+			add("Deployment", appsVersion, deploy.ObjectMeta)
 		}
 	}
 	//deamonsets
@@ -202,7 +374,15 @@ func GetWorkloadGraph(cluster string, namespace string) (Hierarchy, error) {
 		return nil, err
 	} else {
 		for _, daemonset := range deamonsets.Items {
-			hierarchy[daemonset.Namespace] = append(hierarchy[daemonset.Namespace], newHierarchyNode(v1.TypeMeta{APIVersion: appsVersion, Kind: "DaemonSet"}, daemonset.ObjectMeta, ""))
+			add("DaemonSet", appsVersion, daemonset.ObjectMeta)
+		}
+	}
+	//statefulsets
+	if statefulsets, err := api.AppsV1().StatefulSets(namespace).List(context.TODO(), v1.ListOptions{}); err != nil {
+		return nil, err
+	} else {
+		for _, statefulset := range statefulsets.Items {
+			add("StatefulSet", appsVersion, statefulset.ObjectMeta)
 		}
 	}
 	//replicasets
@@ -210,7 +390,23 @@ func GetWorkloadGraph(cluster string, namespace string) (Hierarchy, error) {
 		return nil, err
 	} else {
 		for _, replicaset := range replicasets.Items {
-			hierarchy[replicaset.Namespace] = append(hierarchy[replicaset.Namespace], newHierarchyNode(v1.TypeMeta{APIVersion: appsVersion, Kind: "ReplicaSet"}, replicaset.ObjectMeta, ""))
+			add("ReplicaSet", appsVersion, replicaset.ObjectMeta)
+		}
+	}
+	//cronjobs
+	if cronjobs, err := api.BatchV1().CronJobs(namespace).List(context.TODO(), v1.ListOptions{}); err != nil {
+		return nil, err
+	} else {
+		for _, cronjob := range cronjobs.Items {
+			add("CronJob", batchVersion, cronjob.ObjectMeta)
+		}
+	}
+	//jobs
+	if jobs, err := api.BatchV1().Jobs(namespace).List(context.TODO(), v1.ListOptions{}); err != nil {
+		return nil, err
+	} else {
+		for _, job := range jobs.Items {
+			add("Job", batchVersion, job.ObjectMeta)
 		}
 	}
 	//pods
@@ -218,7 +414,34 @@ func GetWorkloadGraph(cluster string, namespace string) (Hierarchy, error) {
 		return nil, err
 	} else {
 		for _, pod := range pods.Items {
-			hierarchy[pod.Namespace] = append(hierarchy[pod.Namespace], newHierarchyNode(v1.TypeMeta{APIVersion: coreVersion, Kind: "Pod"}, pod.ObjectMeta, ""))
+			add("Pod", coreVersion, pod.ObjectMeta)
+		}
+	}
+
+	// owners not listed above (e.g. custom resources of operators)
+	resolver := newOwnerResolver(client)
+	added := map[string]bool{}
+	for _, nodes := range hierarchy {
+		for _, n := range nodes {
+			added[n.UID] = true
+		}
+	}
+	for _, obj := range owners {
+		ns := obj.Namespace
+		for ref := ownerReferenceOf(obj.OwnerReferences); ref != nil && !added[string(ref.UID)]; {
+			added[string(ref.UID)] = true
+			n := HierarchyNode{UID: string(ref.UID), Name: ref.Name, APIVersion: ref.APIVersion, Kind: ref.Kind, Namespace: ns}
+			owner, err := resolver.get(ns, *ref)
+			if err == nil {
+				n.Namespace = owner.GetNamespace()
+				ref = ownerReferenceOf(owner.GetOwnerReferences())
+				if ref != nil {
+					n.Owner = string(ref.UID)
+				}
+			} else {
+				ref = nil
+			}
+			hierarchy[ns] = append(hierarchy[ns], n)
 		}
 	}
 
@@ -226,7 +449,9 @@ func GetWorkloadGraph(cluster string, namespace string) (Hierarchy, error) {
 
 }
 
-// network graph
+// network graph (routing : ingress-classes -> ingresses, gateways -> routes -> services -> pods)
+//   - ingresses : rules, default backends, tls secrets
+//   - gateway api (gateways, httproutes, grpcroutes) : only if discovered
 func GetNetworkGraph(cluster string, namespace string) (Hierarchy, error) {
 
 	// api-client
@@ -241,6 +466,7 @@ func GetNetworkGraph(cluster string, namespace string) (Hierarchy, error) {
 	if coreVersion, _, networkVersion, err = getGroupVersion(client); err != nil {
 		return nil, err
 	}
+	networkVersion = lang.NVL(networkVersion, networkV1.SchemeGroupVersion.String())
 
 	api, err := client.NewKubernetesClient()
 	if err != nil {
@@ -277,11 +503,100 @@ func GetNetworkGraph(cluster string, namespace string) (Hierarchy, error) {
 	if ingList, err = api.NetworkingV1().Ingresses(namespace).List(context.TODO(), v1.ListOptions{}); err != nil {
 		return nil, err
 	}
+	//ingress-classes (cluster-scoped, optional)
+	classes := map[string]networkV1.IngressClass{}
+	if classList, err := api.NetworkingV1().IngressClasses().List(context.TODO(), v1.ListOptions{}); err == nil {
+		for _, cls := range classList.Items {
+			classes[cls.Name] = cls
+		}
+	}
+
+	// routes -> backend services (by "namespace/name", owner : a route, line : host/path)
+	backends := map[string][]HierarchyNode{}
+	addBackend := func(ns string, kind string, apiGroup string, name string, owner string, line string) {
+		if kind == "" || kind == "Service" {
+			key := ns + "/" + name
+			for i, ref := range backends[key] {
+				if ref.Owner == owner { // paths of a route
+					backends[key][i].Line = strings.Join([]string{ref.Line, line}, ",")
+					return
+				}
+			}
+			backends[key] = append(backends[key], HierarchyNode{Owner: owner, Line: line})
+		} else {
+			hierarchy[ns] = appendHierarchyNode(hierarchy[ns], HierarchyNode{UID: dependencyKey(kind, ns, name), Name: name, APIVersion: apiGroup, Kind: kind, Namespace: ns, Owner: owner, Line: line})
+		}
+	}
 
-	// service->pods
+	// ingress-classes -> ingresses -> services, tls secrets
+	for _, ing := range ingList.Items {
+		n := newHierarchyNode(v1.TypeMeta{APIVersion: networkVersion, Kind: "Ingress"}, ing.ObjectMeta, "")
+		className := ing.Annotations["kubernetes.io/ingress.class"]
+		if ing.Spec.IngressClassName != nil {
+			className = *ing.Spec.IngressClassName
+		}
+		if cls, ok := classes[className]; ok {
+			hierarchy[ing.Namespace] = appendHierarchyNode(hierarchy[ing.Namespace], newHierarchyNode(v1.TypeMeta{APIVersion: networkVersion, Kind: "IngressClass"}, cls.ObjectMeta, ""))
+			n.Owner = string(cls.UID)
+		}
+		if className != "" {
+			n.Attributes = map[string]string{"ingressClass": className}
+		}
+		hierarchy[ing.Namespace] = append(hierarchy[ing.Namespace], n)
+
+		addIngressBackend := func(b *networkV1.IngressBackend, line string) {
+			if b == nil {
+				return
+			} else if b.Service != nil {
+				addBackend(ing.Namespace, "Service", "", b.Service.Name, string(ing.UID), line)
+			} else if b.Resource != nil {
+				apiGroup := ""
+				if b.Resource.APIGroup != nil {
+					apiGroup = *b.Resource.APIGroup
+				}
+				addBackend(ing.Namespace, b.Resource.Kind, apiGroup, b.Resource.Name, string(ing.UID), line)
+			}
+		}
+		addIngressBackend(ing.Spec.DefaultBackend, "(default)")
+		for _, rule := range ing.Spec.Rules {
+			if rule.HTTP == nil {
+				continue // host only (served by a default backend)
+			}
+			for i, path := range rule.HTTP.Paths {
+				line := rule.Host
+				if len(path.Path) > 1 {
+					line = fmt.Sprintf("%s (%s)", lang.NVL(rule.Host, "-"), path.Path)
+				}
+				addIngressBackend(&rule.HTTP.Paths[i].Backend, line)
+			}
+		}
+		for _, tls := range ing.Spec.TLS {
+			if tls.SecretName != "" {
+				hierarchy[ing.Namespace] = appendHierarchyNode(hierarchy[ing.Namespace], HierarchyNode{
+					UID: dependencyKey(ELEMENT_KIND_SECRET, ing.Namespace, tls.SecretName), Name: tls.SecretName, APIVersion: coreVersion, Kind: ELEMENT_KIND_SECRET,
+					Namespace: ing.Namespace, Owner: string(ing.UID), Line: strings.Join(tls.Hosts, ","),
+				})
+			}
+		}
+	}
+
+	// gateways -> routes -> services (optional)
+	if err := getGatewayRoutes(client, namespace, hierarchy, addBackend); err != nil {
+		log.Warnf("Unable to get gateway api routes (cause=%v)", err)
+	}
+
+	// services -> pods
 	for _, svc := range svcList.Items {
 
-		hierarchy[svc.Namespace] = append(hierarchy[svc.Namespace], newHierarchyNode(v1.TypeMeta{APIVersion: coreVersion, Kind: "Service"}, svc.ObjectMeta, ""))
+		if refs := backends[svc.Namespace+"/"+svc.Name]; len(refs) > 0 {
+			for _, ref := range refs {
+				n := newHierarchyNode(v1.TypeMeta{APIVersion: coreVersion, Kind: "Service"}, svc.ObjectMeta, ref.Owner)
+				n.Line = ref.Line
+				hierarchy[svc.Namespace] = appendHierarchyNode(hierarchy[svc.Namespace], n)
+			}
+		} else {
+			hierarchy[svc.Namespace] = append(hierarchy[svc.Namespace], newHierarchyNode(v1.TypeMeta{APIVersion: coreVersion, Kind: "Service"}, svc.ObjectMeta, ""))
+		}
 
 		// get the pods relations
 		if len(svc.Spec.Selector) > 0 {
@@ -299,31 +614,139 @@ func GetNetworkGraph(cluster string, namespace string) (Hierarchy, error) {
 
 	}
 
-	// ingress->services
-	for _, ing := range ingList.Items {
-		hierarchy[ing.Namespace] = append(hierarchy[ing.Namespace], newHierarchyNode(v1.TypeMeta{APIVersion: networkVersion, Kind: "Ingress"}, ing.ObjectMeta, ""))
-		for _, rule := range ing.Spec.Rules {
-			for _, path := range rule.HTTP.Paths {
-				for i, nd := range hierarchy[ing.Namespace] {
-					if nd.Kind == "Service" && path.Backend.Service.Name == nd.Name {
-						if len(path.Path) > 1 {
-							nd.Line = fmt.Sprintf("%s (%s) ", lang.NVL(rule.Host, "-"), path.Path)
-						} else {
-							nd.Line = rule.Host
-						}
-						nd.Owner = string(ing.UID)
-						hierarchy[nd.Namespace] = append(hierarchy[nd.Namespace][:i], append(hierarchy[nd.Namespace][i+1:], nd)...)
+	return hierarchy, err
+}
+
+// gateway api (gateways -> httproutes, grpcroutes -> backends), no-op if gateway api is not discovered
+func getGatewayRoutes(client *config.ClientSet, namespace string, hierarchy Hierarchy, addBackend func(ns string, kind string, apiGroup string, name string, owner string, line string)) error {
+
+	discoveryClient, err := client.NewDiscoveryClient()
+	if err != nil {
+		return err
+	}
+	groups, err := discoveryClient.ServerGroups()
+	if err != nil {
+		return err
+	}
+	gatewayVersion := ""
+	for _, g := range groups.Groups {
+		if g.Name == "gateway.networking.k8s.io" {
+			gatewayVersion = g.PreferredVersion.GroupVersion
+		}
+	}
+	if gatewayVersion == "" {
+		return nil
+	}
+	resources, err := discoveryClient.ServerResourcesForGroupVersion(gatewayVersion)
+	if err != nil {
+		return err
+	}
+	gv, err := schema.ParseGroupVersion(gatewayVersion)
+	if err != nil {
+		return err
+	}
+	list := func(resource string, namespace string) ([]unstructured.Unstructured, error) {
+		for _, r := range resources.APIResources {
+			if r.Name == resource {
+				api, err := client.NewDynamicClientSchema(gv.Group, gv.Version, resource)
+				if err != nil {
+					return nil, err
+				}
+				api.SetNamespace(namespace)
+				if l, err := api.List(v1.ListOptions{}); err != nil {
+					return nil, err
+				} else {
+					return l.Items, nil
+				}
+			}
+		}
+		return []unstructured.Unstructured{}, nil
+	}
+
+	// gateways (cluster-wide, parents of routes in other namespaces)
+	gatewayList, err := list("gateways", "")
+	if err != nil {
+		return err
+	}
+	gateways := map[string]HierarchyNode{}
+	for _, gw := range gatewayList {
+		n := HierarchyNode{UID: string(gw.GetUID()), Name: gw.GetName(), APIVersion: gw.GetAPIVersion(), Kind: gw.GetKind(), Namespace: gw.GetNamespace()}
+		if className, _, _ := unstructured.NestedString(gw.Object, "spec", "gatewayClassName"); className != "" {
+			n.Attributes = map[string]string{"gatewayClass": className}
+		}
+		gateways[gw.GetNamespace()+"/"+gw.GetName()] = n
+		if namespace == "" || gw.GetNamespace() == namespace {
+			hierarchy[gw.GetNamespace()] = appendHierarchyNode(hierarchy[gw.GetNamespace()], n)
+		}
+	}
+
+	// routes
+	for _, resource := range []string{"httproutes", "grpcroutes"} {
+		routes, err := list(resource, namespace)
+		if err != nil {
+			return err
+		}
+		for _, route := range routes {
+			ns := route.GetNamespace()
+			hostnames, _, _ := unstructured.NestedStringSlice(route.Object, "spec", "hostnames")
+			n := HierarchyNode{UID: string(route.GetUID()), Name: route.GetName(), APIVersion: route.GetAPIVersion(), Kind: route.GetKind(), Namespace: ns, Line: strings.Join(hostnames, ",")}
+
+			// parents (gateways)
+			attached := false
+			parentRefs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+			for _, p := range parentRefs {
+				ref, _ := p.(map[string]interface{})
+				kind, _, _ := unstructured.NestedString(ref, "kind")
+				refNamespace, _, _ := unstructured.NestedString(ref, "namespace")
+				name, _, _ := unstructured.NestedString(ref, "name")
+				if gw, ok := gateways[lang.NVL(refNamespace, ns)+"/"+name]; ok && lang.NVL(kind, "Gateway") == "Gateway" {
+					hierarchy[ns] = appendHierarchyNode(hierarchy[ns], gw) // a gateway of other namespace
+					n.Owner = gw.UID
+					hierarchy[ns] = appendHierarchyNode(hierarchy[ns], n)
+					attached = true
+				}
+			}
+			if !attached {
+				hierarchy[ns] = append(hierarchy[ns], n)
+			}
+
+			// rules (matches -> backends)
+			rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+			for _, r := range rules {
+				rule, _ := r.(map[string]interface{})
+				matches := []string{}
+				ms, _, _ := unstructured.NestedSlice(rule, "matches")
+				for _, m := range ms {
+					match, _ := m.(map[string]interface{})
+					if path, _, _ := unstructured.NestedString(match, "path", "value"); path != "" {
+						matches = append(matches, path)
 					}
+					service, _, _ := unstructured.NestedString(match, "method", "service")
+					method, _, _ := unstructured.NestedString(match, "method", "method")
+					if service != "" || method != "" {
+						matches = append(matches, fmt.Sprintf("%s/%s", lang.NVL(service, "*"), lang.NVL(method, "*")))
+					}
+				}
+				backendRefs, _, _ := unstructured.NestedSlice(rule, "backendRefs")
+				for _, b := range backendRefs {
+					ref, _ := b.(map[string]interface{})
+					kind, _, _ := unstructured.NestedString(ref, "kind")
+					group, _, _ := unstructured.NestedString(ref, "group")
+					refNamespace, _, _ := unstructured.NestedString(ref, "namespace")
+					name, _, _ := unstructured.NestedString(ref, "name")
+					addBackend(lang.NVL(refNamespace, ns), kind, group, name, n.UID, strings.Join(matches, ","))
 				}
 			}
 		}
 	}
 
-	return hierarchy, err
+	return nil
+
 }
 
-// workload graph
-func GetPodGraph(cluster string, namespace string, name string) (Hierarchy, error) {
+// storage graph (storageclasses -> persistentvolumes -> persistentvolumeclaims -> pods -> controllers)
+//   - cluster-scoped objects not bound to a namespace (unused storageclasses, unclaimed persistentvolumes) are grouped by "" key
+func GetStorageGraph(cluster string, namespace string) (Hierarchy, error) {
 
 	// api-client
 	client, err := config.Cluster.Client(cluster)
@@ -337,6 +760,217 @@ func GetPodGraph(cluster string, namespace string, name string) (Hierarchy, erro
 	if coreVersion, appsVersion, _, err = getGroupVersion(client); err != nil {
 		return nil, err
 	}
+	storageVersion := storageV1.SchemeGroupVersion.String()
+
+	api, err := client.NewKubernetesClient()
+	if err != nil {
+		return nil, err
+	}
+
+	// namespace list
+	hierarchy := make(map[string][]HierarchyNode)
+	if namespace == "" {
+		if nsList, err := api.CoreV1().Namespaces().List(context.TODO(), v1.ListOptions{}); err != nil {
+			return nil, err
+		} else {
+			for _, ns := range nsList.Items {
+				hierarchy[ns.Name] = []HierarchyNode{}
+			}
+		}
+	} else {
+		hierarchy[namespace] = []HierarchyNode{}
+	}
+
+	scList, err := api.StorageV1().StorageClasses().List(context.TODO(), v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	pvList, err := api.CoreV1().PersistentVolumes().List(context.TODO(), v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	pvcList, err := api.CoreV1().PersistentVolumeClaims(namespace).List(context.TODO(), v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	podList, err := api.CoreV1().Pods(namespace).List(context.TODO(), v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	rsList, err := api.AppsV1().ReplicaSets(namespace).List(context.TODO(), v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// storageclasses, persistentvolumes (by name)
+	scNodes := map[string]HierarchyNode{}
+	for _, sc := range scList.Items {
+		n := newHierarchyNode(v1.TypeMeta{APIVersion: storageVersion, Kind: "StorageClass"}, sc.ObjectMeta, "")
+		n.Attributes = map[string]string{"provisioner": sc.Provisioner}
+		if sc.ReclaimPolicy != nil {
+			n.Attributes["reclaimPolicy"] = string(*sc.ReclaimPolicy)
+		}
+		if sc.VolumeBindingMode != nil {
+			n.Attributes["volumeBindingMode"] = string(*sc.VolumeBindingMode)
+		}
+		scNodes[sc.Name] = n
+	}
+	pvNodes := map[string]HierarchyNode{}
+	for _, pv := range pvList.Items {
+		n := newHierarchyNode(v1.TypeMeta{APIVersion: coreVersion, Kind: "PersistentVolume"}, pv.ObjectMeta, "")
+		if sc, ok := scNodes[pv.Spec.StorageClassName]; ok {
+			n.Owner = sc.UID
+		}
+		capacity := pv.Spec.Capacity[coreV1.ResourceStorage]
+		n.Line = fmt.Sprintf("%s (%s)", capacity.String(), storageAccessModes(pv.Spec.AccessModes))
+		n.Attributes = map[string]string{
+			"capacity":      capacity.String(),
+			"accessModes":   storageAccessModes(pv.Spec.AccessModes),
+			"reclaimPolicy": string(pv.Spec.PersistentVolumeReclaimPolicy),
+			"phase":         string(pv.Status.Phase),
+			"storageClass":  pv.Spec.StorageClassName,
+		}
+		if pv.Spec.ClaimRef == nil || pv.Status.Phase == coreV1.VolumeReleased {
+			n.Attributes["orphaned"] = "true"
+		}
+		pvNodes[pv.Name] = n
+	}
+
+	// pods by persistentvolumeclaim, controllers of pods
+	claimed := map[string][]coreV1.Pod{}
+	for _, pod := range podList.Items {
+		for _, vol := range pod.Spec.Volumes {
+			if vol.PersistentVolumeClaim != nil {
+				key := fmt.Sprintf("%s/%s", pod.Namespace, vol.PersistentVolumeClaim.ClaimName)
+				if n := len(claimed[key]); n == 0 || claimed[key][n-1].UID != pod.UID {
+					claimed[key] = append(claimed[key], pod) // a claim mounted twice by a pod
+				}
+			}
+		}
+	}
+	controller := func(pod coreV1.Pod) *v1.OwnerReference {
+		ref := v1.GetControllerOf(&pod)
+		if ref != nil && ref.Kind == "ReplicaSet" {
+			for i := range rsList.Items {
+				if rsList.Items[i].UID == ref.UID {
+					if owner := v1.GetControllerOf(&rsList.Items[i]); owner != nil {
+						return owner
+					}
+				}
+			}
+		}
+		return ref
+	}
+
+	// persistentvolumeclaims -> pods -> controllers
+	added := map[string]bool{} // storageclasses & persistentvolumes (by namespace)
+	add := func(ns string, n HierarchyNode) {
+		if key := ns + "/" + n.UID; !added[key] {
+			added[key] = true
+			hierarchy[ns] = append(hierarchy[ns], n)
+		}
+	}
+	for _, pvc := range pvcList.Items {
+		n := newHierarchyNode(v1.TypeMeta{APIVersion: coreVersion, Kind: "PersistentVolumeClaim"}, pvc.ObjectMeta, "")
+		capacity := pvc.Status.Capacity[coreV1.ResourceStorage]
+		request := pvc.Spec.Resources.Requests[coreV1.ResourceStorage]
+		n.Attributes = map[string]string{
+			"capacity":    capacity.String(),
+			"request":     request.String(),
+			"accessModes": storageAccessModes(pvc.Status.AccessModes),
+			"phase":       string(pvc.Status.Phase),
+		}
+		scName := ""
+		if pvc.Spec.StorageClassName != nil {
+			scName = *pvc.Spec.StorageClassName
+		}
+		if pv, ok := pvNodes[pvc.Spec.VolumeName]; ok {
+			if sc, ok := scNodes[pv.Attributes["storageClass"]]; ok {
+				add(pvc.Namespace, sc)
+			}
+			add(pvc.Namespace, pv)
+			n.Owner = pv.UID
+		} else if sc, ok := scNodes[scName]; ok {
+			add(pvc.Namespace, sc)
+			n.Owner = sc.UID
+		}
+
+		pods := claimed[fmt.Sprintf("%s/%s", pvc.Namespace, pvc.Name)]
+		if len(pods) == 0 {
+			n.Attributes["orphaned"] = "true"
+		}
+		hierarchy[pvc.Namespace] = append(hierarchy[pvc.Namespace], n)
+
+		for _, pod := range pods {
+			pn := newHierarchyNode(v1.TypeMeta{APIVersion: coreVersion, Kind: "Pod"}, pod.ObjectMeta, string(pvc.UID))
+			pn.Attributes = map[string]string{"phase": string(pod.Status.Phase)}
+			hierarchy[pod.Namespace] = appendHierarchyNode(hierarchy[pod.Namespace], pn)
+			if ref := controller(pod); ref != nil {
+				hierarchy[pod.Namespace] = appendHierarchyNode(hierarchy[pod.Namespace], HierarchyNode{
+					UID: string(ref.UID), Name: ref.Name, APIVersion: lang.NVL(ref.APIVersion, appsVersion), Kind: ref.Kind,
+					Namespace: pod.Namespace, Owner: string(pod.UID),
+				})
+			}
+		}
+	}
+
+	// cluster scope : persistentvolumes not bound to claims, unused storageclasses
+	if namespace == "" {
+		used := map[string]bool{}
+		for _, pvc := range pvcList.Items {
+			if pvc.Spec.StorageClassName != nil {
+				used[*pvc.Spec.StorageClassName] = true
+			}
+		}
+		for _, pv := range pvList.Items {
+			used[pv.Spec.StorageClassName] = true
+			if pvNodes[pv.Name].Attributes["orphaned"] == "true" {
+				if sc, ok := scNodes[pv.Spec.StorageClassName]; ok {
+					add("", sc)
+				}
+				add("", pvNodes[pv.Name])
+			}
+		}
+		for _, sc := range scList.Items {
+			if !used[sc.Name] {
+				add("", scNodes[sc.Name])
+			}
+		}
+	}
+
+	return hierarchy, nil
+
+}
+
+// access modes abbreviations (RWO, ROX, RWX, RWOP)
+func storageAccessModes(modes []coreV1.PersistentVolumeAccessMode) string {
+	abbr := map[coreV1.PersistentVolumeAccessMode]string{
+		coreV1.ReadWriteOnce:    "RWO",
+		coreV1.ReadOnlyMany:     "ROX",
+		coreV1.ReadWriteMany:    "RWX",
+		coreV1.ReadWriteOncePod: "RWOP",
+	}
+	s := []string{}
+	for _, m := range modes {
+		s = append(s, lang.NVL(abbr[m], string(m)))
+	}
+	return strings.Join(s, ",")
+}
+
+// workload graph
+func GetPodGraph(cluster string, namespace string, name string) (Hierarchy, error) {
+
+	// api-client
+	client, err := config.Cluster.Client(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	// get group versions
+	var coreVersion string
+	if coreVersion, _, _, err = getGroupVersion(client); err != nil {
+		return nil, err
+	}
 
 	// get objects
 	nodes := []HierarchyNode{}
@@ -355,37 +989,16 @@ func GetPodGraph(cluster string, namespace string, name string) (Hierarchy, erro
 		nodes = append(nodes, n)
 	}
 
-	//ownerReferences (ReplicaSet+Deployment, DaemonSet, StatefulSet)
-	if len(pod.OwnerReferences) > 0 {
-		owner := pod.OwnerReferences[0]
-
-		if owner.Kind == "ReplicaSet" {
-			if replicaset, err := api.AppsV1().ReplicaSets(namespace).Get(context.TODO(), owner.Name, v1.GetOptions{}); err != nil {
-				return nil, err
-			} else {
-				n1 := newHierarchyNode(v1.TypeMeta{APIVersion: appsVersion, Kind: "ReplicaSet"}, replicaset.ObjectMeta, "")
-				n1.Owner = string(pod.UID)
-				nodes = append(nodes, n1)
-
-				//deployment
-				if len(replicaset.OwnerReferences) > 0 {
-					if deployment, err := api.AppsV1().Deployments(namespace).Get(context.TODO(), replicaset.OwnerReferences[0].Name, v1.GetOptions{}); err == nil {
-						nodes = append(nodes, newHierarchyNode(v1.TypeMeta{APIVersion: appsVersion, Kind: "Deployment"}, deployment.ObjectMeta, string(replicaset.UID)))
-					}
-				}
-			}
-		} else if owner.Kind == "DaemonSet" {
-			if daemonset, err := api.AppsV1().DaemonSets(namespace).Get(context.TODO(), owner.Name, v1.GetOptions{}); err != nil {
-				return nil, err
-			} else {
-				nodes = append(nodes, newHierarchyNode(v1.TypeMeta{APIVersion: appsVersion, Kind: "DaemonSet"}, daemonset.ObjectMeta, string(pod.UID)))
-			}
-		} else if owner.Kind == "StatefulSet" {
-			if statefulset, err := api.AppsV1().StatefulSets(namespace).Get(context.TODO(), owner.Name, v1.GetOptions{}); err != nil {
-				return nil, err
-			} else {
-				nodes = append(nodes, newHierarchyNode(v1.TypeMeta{APIVersion: appsVersion, Kind: "StatefulSet"}, statefulset.ObjectMeta, string(pod.UID)))
-			}
+	//ownerReferences (e.g. ReplicaSet+Deployment, Job+CronJob, custom resources of operators)
+	resolver := newOwnerResolver(client)
+	owned := string(pod.UID)
+	for ref, i := ownerReferenceOf(pod.OwnerReferences), 0; ref != nil && i < ownerChainLimit; i++ {
+		nodes = append(nodes, HierarchyNode{UID: string(ref.UID), Name: ref.Name, APIVersion: ref.APIVersion, Kind: ref.Kind, Namespace: namespace, Owner: owned})
+		owned = string(ref.UID)
+		if owner, err := resolver.get(namespace, *ref); err != nil {
+			break
+		} else {
+			ref = ownerReferenceOf(owner.GetOwnerReferences())
 		}
 	}
 	//ServiceAccount
@@ -418,3 +1031,484 @@ func GetPodGraph(cluster string, namespace string, name string) (Hierarchy, erro
 	return map[string][]HierarchyNode{namespace: nodes}, err
 
 }
+
+// rbac objects (roles, bindings)
+type rbacObjects struct {
+	namespace           string
+	roles               []rbacV1.Role
+	clusterRoles        []rbacV1.ClusterRole
+	roleBindings        []rbacV1.RoleBinding
+	clusterRoleBindings []rbacV1.ClusterRoleBinding
+}
+
+// rbac graph (subjects -> bindings -> roles, aggregated cluster-roles -> cluster-roles)
+func GetRBACGraph(cluster string, namespace string) (Topology, error) {
+
+	rbac, err := getRBACObjects(cluster, namespace)
+	if err != nil {
+		return Topology{}, err
+	}
+	return rbac.topology(nil), nil
+
+}
+
+// who can <verb> <resource> (subjects -> bindings -> roles that allow the attributes)
+func GetWhoCanGraph(cluster string, namespace string, attr AccessAttributes) (Topology, error) {
+
+	if attr.Verb == "" || (attr.Resource == "" && attr.Path == "") {
+		return Topology{}, fmt.Errorf("verb and resource (or path) are required")
+	}
+	// "deployments.apps" -> resource "deployments", group "apps"
+	if attr.Group == "" && strings.Contains(attr.Resource, ".") {
+		s := strings.SplitN(attr.Resource, ".", 2)
+		attr.Resource, attr.Group = s[0], s[1]
+	}
+	// "pods/log" -> resource "pods", subresource "log"
+	if attr.Subresource == "" && strings.Contains(attr.Resource, "/") {
+		s := strings.SplitN(attr.Resource, "/", 2)
+		attr.Resource, attr.Subresource = s[0], s[1]
+	}
+
+	rbac, err := getRBACObjects(cluster, namespace)
+	if err != nil {
+		return Topology{}, err
+	}
+	return rbac.topology(func(rules []rbacV1.PolicyRule) bool {
+		for _, rule := range rules {
+			if rbacRuleAllows(rule, attr) {
+				return true
+			}
+		}
+		return false
+	}), nil
+
+}
+
+func getRBACObjects(cluster string, namespace string) (*rbacObjects, error) {
+
+	client, err := config.Cluster.Client(cluster)
+	if err != nil {
+		return nil, err
+	}
+	api, err := client.NewKubernetesClient()
+	if err != nil {
+		return nil, err
+	}
+
+	rbac := &rbacObjects{namespace: namespace}
+	if roles, err := api.RbacV1().Roles(namespace).List(context.TODO(), v1.ListOptions{}); err != nil {
+		return nil, err
+	} else {
+		rbac.roles = roles.Items
+	}
+	if clusterRoles, err := api.RbacV1().ClusterRoles().List(context.TODO(), v1.ListOptions{}); err != nil {
+		return nil, err
+	} else {
+		rbac.clusterRoles = clusterRoles.Items
+	}
+	if roleBindings, err := api.RbacV1().RoleBindings(namespace).List(context.TODO(), v1.ListOptions{}); err != nil {
+		return nil, err
+	} else {
+		rbac.roleBindings = roleBindings.Items
+	}
+	if clusterRoleBindings, err := api.RbacV1().ClusterRoleBindings().List(context.TODO(), v1.ListOptions{}); err != nil {
+		return nil, err
+	} else {
+		rbac.clusterRoleBindings = clusterRoleBindings.Items
+	}
+
+	return rbac, nil
+
+}
+
+// topology of rbac objects (filter : rules of a role, nil = all)
+func (me *rbacObjects) topology(filter func(rules []rbacV1.PolicyRule) bool) Topology {
+
+	topology := Topology{Nodes: []topologyNode{}, Links: []topologyLink{}}
+	added := map[string]bool{}
+	addNode := func(id string, name string, kind string, namespace string) {
+		if !added[id] {
+			added[id] = true
+			topology.Nodes = append(topology.Nodes, topologyNode{Id: id, Name: name, Kind: kind, Namespace: namespace, Group: namespace})
+		}
+	}
+	addLink := func(source string, target string, kind string) {
+		topology.Links = append(topology.Links, topologyLink{Source: source, Target: target, Kind: kind})
+	}
+
+	// roles (key = kind:namespace/name)
+	roles := map[string]*rbacV1.ClusterRole{}
+	for i := range me.roles {
+		r := me.roles[i]
+		roles[rbacRoleKey(ELEMENT_KIND_ROLE, r.Namespace, r.Name)] = &rbacV1.ClusterRole{ObjectMeta: r.ObjectMeta, Rules: r.Rules}
+	}
+	for i := range me.clusterRoles {
+		roles[rbacRoleKey(ELEMENT_KIND_CLUSTER_ROLE, "", me.clusterRoles[i].Name)] = &me.clusterRoles[i]
+	}
+	addRole := func(kind string, namespace string, name string) (string, bool) {
+		key := rbacRoleKey(kind, namespace, name)
+		role := roles[key]
+		if role == nil {
+			// dangling reference
+			if filter != nil {
+				return "", false
+			}
+			addNode(key, name, kind, namespace)
+			return key, true
+		}
+		if filter != nil && !filter(role.Rules) {
+			return "", false
+		}
+		addNode(string(role.UID), role.Name, kind, role.Namespace)
+		return string(role.UID), true
+	}
+
+	// bindings
+	addBinding := func(kind string, obj v1.ObjectMeta, roleRef rbacV1.RoleRef, subjects []rbacV1.Subject) {
+		roleNamespace := ""
+		if roleRef.Kind == ELEMENT_KIND_ROLE {
+			roleNamespace = obj.Namespace
+		}
+		roleID, ok := addRole(roleRef.Kind, roleNamespace, roleRef.Name)
+		if !ok {
+			return
+		}
+		bindingID := string(obj.UID)
+		addNode(bindingID, obj.Name, kind, obj.Namespace)
+		addLink(bindingID, roleID, kind)
+		for _, s := range subjects {
+			subjectID, subjectNamespace := rbacSubjectKey(s, obj.Namespace)
+			addNode(subjectID, s.Name, s.Kind, subjectNamespace)
+			addLink(subjectID, bindingID, s.Kind)
+		}
+	}
+	if filter == nil || me.namespace != "" {
+		for _, b := range me.roleBindings {
+			addBinding(ELEMENT_KIND_ROLE_BINDING, b.ObjectMeta, b.RoleRef, b.Subjects)
+		}
+	}
+	for _, b := range me.clusterRoleBindings {
+		addBinding(ELEMENT_KIND_CLUSTER_ROLE_BINDING, b.ObjectMeta, b.RoleRef, b.Subjects)
+	}
+
+	// unbound roles (namespace)
+	if filter == nil {
+		for _, r := range me.roles {
+			addRole(ELEMENT_KIND_ROLE, r.Namespace, r.Name)
+		}
+	}
+
+	// aggregated cluster-roles : component --> aggregate
+	for _, aggregate := range me.clusterRoles {
+		if aggregate.AggregationRule == nil || !added[string(aggregate.UID)] {
+			continue
+		}
+		for _, s := range aggregate.AggregationRule.ClusterRoleSelectors {
+			selector, err := v1.LabelSelectorAsSelector(&s)
+			if err != nil {
+				continue
+			}
+			for _, component := range me.clusterRoles {
+				if component.UID == aggregate.UID || !selector.Matches(labels.Set(component.Labels)) {
+					continue
+				}
+				if filter != nil && !filter(component.Rules) {
+					continue
+				}
+				addNode(string(component.UID), component.Name, ELEMENT_KIND_CLUSTER_ROLE, "")
+				addLink(string(component.UID), string(aggregate.UID), ELEMENT_KIND_CLUSTER_ROLE)
+			}
+		}
+	}
+
+	return topology
+
+}
+
+func rbacRoleKey(kind string, namespace string, name string) string {
+	if namespace == "" {
+		return fmt.Sprintf("%s:%s", kind, name)
+	}
+	return fmt.Sprintf("%s:%s/%s", kind, namespace, name)
+}
+
+// subject id & namespace
+func rbacSubjectKey(s rbacV1.Subject, namespace string) (string, string) {
+	if s.Kind == ELEMENT_KIND_SERVICE_ACCOUNT {
+		ns := lang.NVL(s.Namespace, namespace)
+		return fmt.Sprintf("%s:%s/%s", s.Kind, ns, s.Name), ns
+	}
+	return fmt.Sprintf("%s:%s", s.Kind, s.Name), ""
+}
+
+// a policy rule allows attributes
+func rbacRuleAllows(rule rbacV1.PolicyRule, attr AccessAttributes) bool {
+
+	if !rbacMatches(rule.Verbs, attr.Verb) {
+		return false
+	}
+
+	// non-resource url
+	if attr.Path != "" {
+		for _, url := range rule.NonResourceURLs {
+			if url == "*" || url == attr.Path || (strings.HasSuffix(url, "*") && strings.HasPrefix(attr.Path, strings.TrimSuffix(url, "*"))) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if !rbacMatches(rule.APIGroups, attr.Group) {
+		return false
+	}
+	resource := attr.Resource
+	if attr.Subresource != "" {
+		resource = fmt.Sprintf("%s/%s", attr.Resource, attr.Subresource)
+	}
+	matched := false
+	for _, r := range rule.Resources {
+		if r == "*" || r == resource || (attr.Subresource != "" && r == "*/"+attr.Subresource) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return false
+	}
+	return len(rule.ResourceNames) == 0 || (attr.Name != "" && lang.ArrayContains(rule.ResourceNames, attr.Name))
+
+}
+
+func rbacMatches(values []string, value string) bool {
+	for _, v := range values {
+		if v == "*" || v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// dependency graph (pods -> configmaps, secrets, pvcs, service accounts / services -> pods / ingresses -> services)
+//   - focus (kind, namespace, name) : a focused object and objects depend on it (blast radius)
+func GetDependencyGraph(cluster string, namespace string, kind string, focusNamespace string, name string) (Topology, error) {
+
+	topology := Topology{Nodes: []topologyNode{}, Links: []topologyLink{}}
+
+	// validate a focus (kind is case-insensitive)
+	if kind != "" || name != "" {
+		if kind == "" || name == "" || focusNamespace == "" {
+			return topology, errors.NewBadRequest("kind, namespace and name are required to focus")
+		}
+		focusKind := ""
+		for _, k := range dependencyKinds {
+			if strings.EqualFold(k, kind) {
+				focusKind = k
+			}
+		}
+		if focusKind == "" {
+			return topology, errors.NewBadRequest(fmt.Sprintf("unsupported kind '%s' (%s)", kind, strings.Join(dependencyKinds, ", ")))
+		}
+		kind = focusKind
+	}
+
+	client, err := config.Cluster.Client(cluster)
+	if err != nil {
+		return topology, err
+	}
+	api, err := client.NewKubernetesClient()
+	if err != nil {
+		return topology, err
+	}
+
+	podList, err := api.CoreV1().Pods(namespace).List(context.TODO(), v1.ListOptions{})
+	if err != nil {
+		return topology, err
+	}
+	svcList, err := api.CoreV1().Services(namespace).List(context.TODO(), v1.ListOptions{})
+	if err != nil {
+		return topology, err
+	}
+	ingList, err := api.NetworkingV1().Ingresses(namespace).List(context.TODO(), v1.ListOptions{})
+	if err != nil {
+		return topology, err
+	}
+
+	added := map[string]bool{}
+	linked := map[string]bool{}
+	addNode := func(kind string, namespace string, name string) string {
+		id := dependencyKey(kind, namespace, name)
+		if !added[id] {
+			added[id] = true
+			topology.Nodes = append(topology.Nodes, topologyNode{Id: id, Name: name, Kind: kind, Namespace: namespace, Group: namespace})
+		}
+		return id
+	}
+	addLink := func(source string, target string, kind string) {
+		if key := fmt.Sprintf("%s>%s>%s", source, target, kind); !linked[key] {
+			linked[key] = true
+			topology.Links = append(topology.Links, topologyLink{Source: source, Target: target, Kind: kind})
+		}
+	}
+
+	// pod -> configmaps, secrets, pvcs, service account
+	for _, pod := range podList.Items {
+		podID := addNode(ELEMENT_KIND_POD, pod.Namespace, pod.Name)
+		for _, ref := range getPodDependencies(pod) {
+			addLink(podID, addNode(ref.kind, pod.Namespace, ref.name), ref.via)
+		}
+	}
+
+	// service -> pods (selector)
+	for _, svc := range svcList.Items {
+		svcID := addNode(ELEMENT_KIND_SERVICE, svc.Namespace, svc.Name)
+		if len(svc.Spec.Selector) == 0 {
+			continue
+		}
+		selector := labels.SelectorFromSet(svc.Spec.Selector)
+		for _, pod := range podList.Items {
+			if pod.Namespace == svc.Namespace && selector.Matches(labels.Set(pod.Labels)) {
+				addLink(svcID, dependencyKey(ELEMENT_KIND_POD, pod.Namespace, pod.Name), "selector")
+			}
+		}
+	}
+
+	// ingress -> services (backends), secrets (tls)
+	for _, ing := range ingList.Items {
+		ingID := addNode(ELEMENT_KIND_INGRESS, ing.Namespace, ing.Name)
+		backends := []*networkV1.IngressBackend{ing.Spec.DefaultBackend}
+		for _, rule := range ing.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			for i := range rule.HTTP.Paths {
+				backends = append(backends, &rule.HTTP.Paths[i].Backend)
+			}
+		}
+		for _, b := range backends {
+			if b != nil && b.Service != nil {
+				addLink(ingID, addNode(ELEMENT_KIND_SERVICE, ing.Namespace, b.Service.Name), "backend")
+			}
+		}
+		for _, tls := range ing.Spec.TLS {
+			if tls.SecretName != "" {
+				addLink(ingID, addNode(ELEMENT_KIND_SECRET, ing.Namespace, tls.SecretName), "tls")
+			}
+		}
+	}
+
+	if kind == "" {
+		return topology, nil
+	}
+	focused := focusTopology(topology, dependencyKey(kind, focusNamespace, name))
+	if len(focused.Nodes) == 0 {
+		return focused, errors.NewNotFound(schema.GroupResource{Resource: strings.ToLower(kind)}, focusNamespace+"/"+name)
+	}
+	return focused, nil
+
+}
+
+// kinds of dependency graph nodes
+var dependencyKinds = []string{ELEMENT_KIND_POD, ELEMENT_KIND_SERVICE, ELEMENT_KIND_INGRESS, ELEMENT_KIND_CONFIGMAP, ELEMENT_KIND_SECRET, ELEMENT_KIND_PERSISTENT_VOLUME_CLAIM, ELEMENT_KIND_SERVICE_ACCOUNT}
+
+type podDependency struct {
+	kind string
+	name string
+	via  string // volume, env, envFrom, imagePullSecret, serviceAccount
+}
+
+// configmaps, secrets, pvcs and a service account referenced by a pod
+func getPodDependencies(pod coreV1.Pod) []podDependency {
+
+	refs := []podDependency{{kind: ELEMENT_KIND_SERVICE_ACCOUNT, name: lang.NVL(pod.Spec.ServiceAccountName, "default"), via: "serviceAccount"}}
+
+	// volumes
+	for _, vol := range pod.Spec.Volumes {
+		if vol.ConfigMap != nil {
+			refs = append(refs, podDependency{ELEMENT_KIND_CONFIGMAP, vol.ConfigMap.Name, "volume"})
+		} else if vol.Secret != nil {
+			refs = append(refs, podDependency{ELEMENT_KIND_SECRET, vol.Secret.SecretName, "volume"})
+		} else if vol.PersistentVolumeClaim != nil {
+			refs = append(refs, podDependency{ELEMENT_KIND_PERSISTENT_VOLUME_CLAIM, vol.PersistentVolumeClaim.ClaimName, "volume"})
+		} else if vol.Projected != nil {
+			for _, src := range vol.Projected.Sources {
+				if src.ConfigMap != nil {
+					refs = append(refs, podDependency{ELEMENT_KIND_CONFIGMAP, src.ConfigMap.Name, "volume"})
+				} else if src.Secret != nil {
+					refs = append(refs, podDependency{ELEMENT_KIND_SECRET, src.Secret.Name, "volume"})
+				}
+			}
+		}
+	}
+
+	// env, envFrom (init, ephemeral containers included)
+	containers := append(append([]coreV1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	for _, c := range pod.Spec.EphemeralContainers {
+		containers = append(containers, coreV1.Container{Env: c.Env, EnvFrom: c.EnvFrom})
+	}
+	for _, c := range containers {
+		for _, env := range c.EnvFrom {
+			if env.ConfigMapRef != nil {
+				refs = append(refs, podDependency{ELEMENT_KIND_CONFIGMAP, env.ConfigMapRef.Name, "envFrom"})
+			} else if env.SecretRef != nil {
+				refs = append(refs, podDependency{ELEMENT_KIND_SECRET, env.SecretRef.Name, "envFrom"})
+			}
+		}
+		for _, env := range c.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if env.ValueFrom.ConfigMapKeyRef != nil {
+				refs = append(refs, podDependency{ELEMENT_KIND_CONFIGMAP, env.ValueFrom.ConfigMapKeyRef.Name, "env"})
+			} else if env.ValueFrom.SecretKeyRef != nil {
+				refs = append(refs, podDependency{ELEMENT_KIND_SECRET, env.ValueFrom.SecretKeyRef.Name, "env"})
+			}
+		}
+	}
+
+	// image pull secrets
+	for _, s := range pod.Spec.ImagePullSecrets {
+		refs = append(refs, podDependency{ELEMENT_KIND_SECRET, s.Name, "imagePullSecret"})
+	}
+
+	return refs
+
+}
+
+func dependencyKey(kind string, namespace string, name string) string {
+	return fmt.Sprintf("%s:%s/%s", kind, namespace, name)
+}
+
+// a focused node (id) and nodes reachable to it (sources of links, transitively)
+func focusTopology(topology Topology, id string) Topology {
+
+	focused := map[string]bool{}
+	queue := []string{}
+	for _, n := range topology.Nodes {
+		if n.Id == id {
+			focused[n.Id] = true
+			queue = append(queue, n.Id)
+		}
+	}
+	for ; len(queue) > 0; queue = queue[1:] {
+		for _, l := range topology.Links {
+			if l.Target == queue[0] && !focused[l.Source] {
+				focused[l.Source] = true
+				queue = append(queue, l.Source)
+			}
+		}
+	}
+
+	result := Topology{Nodes: []topologyNode{}, Links: []topologyLink{}}
+	for _, n := range topology.Nodes {
+		if focused[n.Id] {
+			result.Nodes = append(result.Nodes, n)
+		}
+	}
+	for _, l := range topology.Links {
+		if focused[l.Source] && focused[l.Target] {
+			result.Links = append(result.Links, l)
+		}
+	}
+	return result
+
+}
@@ -0,0 +1,233 @@
+package model
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	GRAPH_FORMAT_JSON    = "json"
+	GRAPH_FORMAT_DOT     = "dot"
+	GRAPH_FORMAT_GRAPHML = "graphml"
+	GRAPH_FORMAT_MERMAID = "mermaid"
+)
+
+// content-types & file extensions of graph formats
+var GraphFormats = map[string]struct {
+	ContentType string
+	Extension   string
+}{
+	GRAPH_FORMAT_DOT:     {"text/vnd.graphviz; charset=utf-8", "dot"},
+	GRAPH_FORMAT_GRAPHML: {"application/graphml+xml; charset=utf-8", "graphml"},
+	GRAPH_FORMAT_MERMAID: {"text/plain; charset=utf-8", "mmd"},
+}
+
+// graphs exportable to text formats (topology, hierarchy)
+type Graph interface {
+	Export(format string) ([]byte, error)
+}
+
+// nodes & edges (common form of topology and hierarchy graphs)
+type graphElements struct {
+	nodes []graphNode
+	edges []graphEdge
+}
+type graphNode struct {
+	id    string
+	kind  string
+	name  string
+	group string // namespace
+}
+type graphEdge struct {
+	source string
+	target string
+	label  string
+}
+
+func (me Topology) Export(format string) ([]byte, error) {
+
+	elements := graphElements{}
+	for _, n := range me.Nodes {
+		elements.nodes = append(elements.nodes, graphNode{id: n.Id, kind: n.Kind, name: n.Name, group: n.Namespace})
+	}
+	for _, l := range me.Links {
+		if !l.Hidden {
+			elements.edges = append(elements.edges, graphEdge{source: l.Source, target: l.Target, label: l.Kind})
+		}
+	}
+	return elements.export(format)
+
+}
+
+// a node appears once (first one), owners -> nodes are edges
+func (me Hierarchy) Export(format string) ([]byte, error) {
+
+	keys := []string{}
+	for k := range me {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	elements := graphElements{}
+	added := map[string]bool{}
+	for _, k := range keys {
+		for _, n := range me[k] {
+			if !added[n.UID] {
+				added[n.UID] = true
+				elements.nodes = append(elements.nodes, graphNode{id: n.UID, kind: n.Kind, name: n.Name, group: k})
+			}
+			if n.Owner != "" {
+				elements.edges = append(elements.edges, graphEdge{source: n.Owner, target: n.UID, label: n.Line})
+			}
+		}
+	}
+	return elements.export(format)
+
+}
+
+func (me graphElements) export(format string) ([]byte, error) {
+
+	// skip edges of unknown nodes
+	nodes := map[string]bool{}
+	for _, n := range me.nodes {
+		nodes[n.id] = true
+	}
+	edges := []graphEdge{}
+	for _, e := range me.edges {
+		if nodes[e.source] && nodes[e.target] {
+			edges = append(edges, e)
+		}
+	}
+	me.edges = edges
+
+	switch strings.ToLower(format) {
+	case GRAPH_FORMAT_DOT:
+		return me.dot(), nil
+	case GRAPH_FORMAT_GRAPHML:
+		return me.graphml(), nil
+	case GRAPH_FORMAT_MERMAID:
+		return me.mermaid(), nil
+	}
+	return nil, errors.New(fmt.Sprintf("unsupported format '%s' (json, dot, graphml, mermaid)", format))
+
+}
+
+// nodes by group (groups sorted, "" : no group)
+func (me graphElements) groups() ([]string, map[string][]graphNode) {
+	groups := []string{}
+	nodes := map[string][]graphNode{}
+	for _, n := range me.nodes {
+		if _, ok := nodes[n.group]; !ok {
+			groups = append(groups, n.group)
+		}
+		nodes[n.group] = append(nodes[n.group], n)
+	}
+	sort.Strings(groups)
+	return groups, nodes
+}
+
+// graphviz dot (namespaces are clusters)
+func (me graphElements) dot() []byte {
+
+	quote := func(s string) string {
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+	}
+
+	var b bytes.Buffer
+	b.WriteString("digraph G {\n\trankdir=LR;\n\tnode [shape=box];\n")
+	groups, nodes := me.groups()
+	for i, g := range groups {
+		indent := "\t"
+		if g != "" {
+			fmt.Fprintf(&b, "\tsubgraph cluster_%d {\n\t\tlabel=%s;\n", i, quote(g))
+			indent = "\t\t"
+		}
+		for _, n := range nodes[g] {
+			fmt.Fprintf(&b, "%s%s [label=%s];\n", indent, quote(n.id), quote(n.kind+"\n"+n.name))
+		}
+		if g != "" {
+			b.WriteString("\t}\n")
+		}
+	}
+	for _, e := range me.edges {
+		if e.label != "" {
+			fmt.Fprintf(&b, "\t%s -> %s [label=%s];\n", quote(e.source), quote(e.target), quote(e.label))
+		} else {
+			fmt.Fprintf(&b, "\t%s -> %s;\n", quote(e.source), quote(e.target))
+		}
+	}
+	b.WriteString("}\n")
+	return b.Bytes()
+
+}
+
+// graphml (kind, name, namespace node attributes)
+func (me graphElements) graphml() []byte {
+
+	escape := func(s string) string {
+		var b bytes.Buffer
+		xml.EscapeText(&b, []byte(s))
+		return b.String()
+	}
+
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">` + "\n")
+	b.WriteString(`  <key id="kind" for="node" attr.name="kind" attr.type="string"/>` + "\n")
+	b.WriteString(`  <key id="name" for="node" attr.name="name" attr.type="string"/>` + "\n")
+	b.WriteString(`  <key id="namespace" for="node" attr.name="namespace" attr.type="string"/>` + "\n")
+	b.WriteString(`  <key id="label" for="edge" attr.name="label" attr.type="string"/>` + "\n")
+	b.WriteString(`  <graph id="G" edgedefault="directed">` + "\n")
+	for _, n := range me.nodes {
+		fmt.Fprintf(&b, "    <node id=\"%s\"><data key=\"kind\">%s</data><data key=\"name\">%s</data><data key=\"namespace\">%s</data></node>\n", escape(n.id), escape(n.kind), escape(n.name), escape(n.group))
+	}
+	for i, e := range me.edges {
+		fmt.Fprintf(&b, "    <edge id=\"e%d\" source=\"%s\" target=\"%s\"><data key=\"label\">%s</data></edge>\n", i, escape(e.source), escape(e.target), escape(e.label))
+	}
+	b.WriteString("  </graph>\n</graphml>\n")
+	return b.Bytes()
+
+}
+
+// mermaid flowchart (namespaces are subgraphs, node ids are replaced by "n<index>")
+func (me graphElements) mermaid() []byte {
+
+	quote := func(s string) string {
+		return `"` + strings.NewReplacer(`"`, "#quot;", "\n", "<br/>").Replace(s) + `"`
+	}
+
+	ids := map[string]string{}
+	for i, n := range me.nodes {
+		ids[n.id] = fmt.Sprintf("n%d", i)
+	}
+
+	var b bytes.Buffer
+	b.WriteString("flowchart LR\n")
+	groups, nodes := me.groups()
+	for i, g := range groups {
+		indent := "  "
+		if g != "" {
+			fmt.Fprintf(&b, "  subgraph g%d [%s]\n", i, quote(g))
+			indent = "    "
+		}
+		for _, n := range nodes[g] {
+			fmt.Fprintf(&b, "%s%s[%s]\n", indent, ids[n.id], quote(n.kind+": "+n.name))
+		}
+		if g != "" {
+			b.WriteString("  end\n")
+		}
+	}
+	for _, e := range me.edges {
+		if e.label != "" {
+			fmt.Fprintf(&b, "  %s -->|%s| %s\n", ids[e.source], quote(e.label), ids[e.target])
+		} else {
+			fmt.Fprintf(&b, "  %s --> %s\n", ids[e.source], ids[e.target])
+		}
+	}
+	return b.Bytes()
+
+}
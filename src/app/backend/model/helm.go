@@ -0,0 +1,364 @@
+package model
+
+// helm 3 release storage : https://github.com/helm/helm/blob/main/pkg/storage/driver/util.go
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kore3lab/dashboard/pkg/config"
+	log "github.com/sirupsen/logrus"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	HELM_STORAGE_SECRET    = "Secret"
+	HELM_STORAGE_CONFIGMAP = "ConfigMap"
+)
+
+var helmMagicGzip = []byte{0x1f, 0x8b, 0x08}
+
+// yaml documents separator of release manifests
+var helmManifestSeparator = regexp.MustCompile(`(?m)^---[ \t]*$`)
+
+// helm release (decoded "release" data)
+type helmRelease struct {
+	Name string `json:"name"`
+	Info struct {
+		FirstDeployed string `json:"first_deployed"`
+		LastDeployed  string `json:"last_deployed"`
+		Deleted       string `json:"deleted"`
+		Description   string `json:"description"`
+		Status        string `json:"status"`
+		Notes         string `json:"notes"`
+	} `json:"info"`
+	Chart struct {
+		Metadata HelmChart              `json:"metadata"`
+		Values   map[string]interface{} `json:"values"`
+	} `json:"chart"`
+	Config    map[string]interface{} `json:"config"`
+	Manifest  string                 `json:"manifest"`
+	Version   int                    `json:"version"`
+	Namespace string                 `json:"namespace"`
+}
+
+type HelmChart struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	AppVersion  string `json:"appVersion"`
+	Description string `json:"description"`
+	Icon        string `json:"icon"`
+}
+
+type HelmRelease struct {
+	Name          string    `json:"name"`
+	Namespace     string    `json:"namespace"`
+	Revision      int       `json:"revision"`
+	Status        string    `json:"status"`
+	Chart         HelmChart `json:"chart"`
+	FirstDeployed string    `json:"firstDeployed"`
+	LastDeployed  string    `json:"lastDeployed"`
+	Description   string    `json:"description"`
+	Storage       string    `json:"storage"`
+}
+
+type HelmReleaseDetail struct {
+	HelmRelease
+	Notes         string                 `json:"notes"`
+	Manifest      string                 `json:"manifest"`
+	Values        map[string]interface{} `json:"values"`
+	DefaultValues map[string]interface{} `json:"defaultValues"`
+	Redacted      bool                   `json:"redacted,omitempty"`
+}
+
+// release list (latest revisions)
+func GetHelmReleases(cluster string, namespace string) ([]HelmRelease, error) {
+
+	releases, err := getHelmReleases(cluster, namespace, "")
+	if err != nil {
+		return nil, err
+	}
+
+	latest := map[string]HelmRelease{}
+	for _, r := range releases {
+		key := fmt.Sprintf("%s/%s", r.Namespace, r.Name)
+		if l, exist := latest[key]; !exist || l.Revision < r.Revision {
+			latest[key] = r.HelmRelease
+		}
+	}
+
+	list := []HelmRelease{}
+	for _, r := range latest {
+		list = append(list, r)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Namespace == list[j].Namespace {
+			return list[i].Name < list[j].Name
+		}
+		return list[i].Namespace < list[j].Namespace
+	})
+	return list, nil
+
+}
+
+// release revision history
+func GetHelmReleaseHistory(cluster string, namespace string, name string) ([]HelmRelease, error) {
+
+	releases, err := getHelmReleases(cluster, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("release '%s' not found in namespace '%s'", name, namespace)
+	}
+
+	list := []HelmRelease{}
+	for _, r := range releases {
+		list = append(list, r.HelmRelease)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Revision > list[j].Revision })
+	return list, nil
+
+}
+
+// a release revision (manifest, values, notes)
+func GetHelmRelease(cluster string, namespace string, name string, revision int) (*HelmReleaseDetail, error) {
+
+	releases, err := getHelmReleases(cluster, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var detail *HelmReleaseDetail
+	for i := range releases {
+		// revision <= 0 : latest revision
+		if (revision <= 0 && (detail == nil || detail.Revision < releases[i].Revision)) || releases[i].Revision == revision {
+			detail = &releases[i]
+		}
+	}
+	if detail == nil {
+		return nil, fmt.Errorf("release '%s' (revision=%d) not found in namespace '%s'", name, revision, namespace)
+	}
+	return detail, nil
+
+}
+
+// decode releases from storage (secrets, configmaps)
+func getHelmReleases(cluster string, namespace string, name string) ([]HelmReleaseDetail, error) {
+
+	client, err := config.Cluster.Client(cluster)
+	if err != nil {
+		return nil, err
+	}
+	apiClient, err := client.NewKubernetesClient()
+	if err != nil {
+		return nil, err
+	}
+
+	selector := "owner=helm"
+	if name != "" {
+		selector = fmt.Sprintf("%s,name=%s", selector, name)
+	}
+	options := metaV1.ListOptions{LabelSelector: selector}
+
+	releases := []HelmReleaseDetail{}
+
+	// storage driver "secret" (default)
+	secrets, err := apiClient.CoreV1().Secrets(namespace).List(context.TODO(), options)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range secrets.Items {
+		if r, err := decodeHelmRelease(string(s.Data["release"])); err == nil {
+			releases = append(releases, toHelmReleaseDetail(r, s.Namespace, HELM_STORAGE_SECRET))
+		}
+	}
+
+	// storage driver "configmap"
+	configmaps, err := apiClient.CoreV1().ConfigMaps(namespace).List(context.TODO(), options)
+	if err != nil {
+		log.Warnf("Unable to get helm release configmaps (cause=%v)", err)
+	} else {
+		for _, cm := range configmaps.Items {
+			if r, err := decodeHelmRelease(cm.Data["release"]); err == nil {
+				releases = append(releases, toHelmReleaseDetail(r, cm.Namespace, HELM_STORAGE_CONFIGMAP))
+			}
+		}
+	}
+
+	return releases, nil
+
+}
+
+func toHelmReleaseDetail(r *helmRelease, namespace string, storage string) HelmReleaseDetail {
+
+	detail := HelmReleaseDetail{
+		HelmRelease: HelmRelease{
+			Name:          r.Name,
+			Namespace:     r.Namespace,
+			Revision:      r.Version,
+			Status:        r.Info.Status,
+			Chart:         r.Chart.Metadata,
+			FirstDeployed: r.Info.FirstDeployed,
+			LastDeployed:  r.Info.LastDeployed,
+			Description:   r.Info.Description,
+			Storage:       storage,
+		},
+		Notes:         r.Info.Notes,
+		Manifest:      r.Manifest,
+		Values:        r.Config,
+		DefaultValues: r.Chart.Values,
+	}
+	if detail.Namespace == "" {
+		detail.Namespace = namespace
+	}
+	if detail.Values == nil {
+		detail.Values = map[string]interface{}{}
+	}
+
+	// secrets in manifest and sensitive keys of user supplied values are redacted
+	detail.Manifest = redactHelmManifest(detail.Manifest)
+	if config.Value.RedactSecrets {
+		var redacted bool
+		detail.Values, redacted = redactHelmValues(detail.Values)
+		detail.Redacted = redacted
+	}
+	return detail
+
+}
+
+// base64 -> gzip (optional) -> json
+func decodeHelmRelease(data string) (*helmRelease, error) {
+
+	b, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b) > 3 && bytes.Equal(b[0:3], helmMagicGzip) {
+		r, err := gzip.NewReader(bytes.NewReader(b))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		if b, err = ioutil.ReadAll(r); err != nil {
+			return nil, err
+		}
+	}
+
+	release := &helmRelease{}
+	if err := json.Unmarshal(b, release); err != nil {
+		return nil, err
+	}
+	return release, nil
+
+}
+
+// redact secrets & sensitive configmap keys in manifest documents
+func redactHelmManifest(manifest string) string {
+
+	docs := helmManifestSeparator.Split(manifest, -1)
+	for i, doc := range docs {
+		obj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal([]byte(doc), &obj.Object); err != nil || len(obj.Object) == 0 {
+			continue
+		}
+		RedactObject(obj)
+		if obj.GetAnnotations()[REDACTED_ANNOTATION] == "" {
+			continue
+		}
+		b, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			continue
+		}
+		// keep leading comments (eg. "# Source: ...")
+		header := "\n"
+		for _, line := range strings.Split(strings.TrimLeft(doc, "\n"), "\n") {
+			if !strings.HasPrefix(line, "#") {
+				break
+			}
+			header += line + "\n"
+		}
+		docs[i] = header + string(b)
+	}
+	return strings.Join(docs, "---")
+
+}
+
+// key patterns of sensitive helm values (lower case, with "redact-configmap-keys")
+var helmSensitiveKeys = []string{"*password*", "*passwd*", "*secret*", "*token*", "*credential*", "*apikey*", "*api_key*", "*privatekey*", "*private_key*", "*.key", "*.pem"}
+
+func isSensitiveHelmKey(key string) bool {
+	lower := strings.ToLower(key)
+	for _, pattern := range helmSensitiveKeys {
+		if matched, _ := path.Match(pattern, lower); matched {
+			return true
+		}
+	}
+	return isSensitiveConfigMapKey(key)
+}
+
+// redact values of sensitive keys (whole sub-tree) in user supplied values
+func redactHelmValues(values map[string]interface{}) (map[string]interface{}, bool) {
+
+	redacted := false
+	var redact func(v interface{}) interface{}
+	redact = func(v interface{}) interface{} {
+		switch value := v.(type) {
+		case map[string]interface{}:
+			out := map[string]interface{}{}
+			for k, e := range value {
+				if e != nil && isSensitiveHelmKey(k) {
+					out[k] = redactHelmValue(e)
+					redacted = true
+				} else {
+					out[k] = redact(e)
+				}
+			}
+			return out
+		case []interface{}:
+			out := make([]interface{}, len(value))
+			for i, e := range value {
+				out[i] = redact(e)
+			}
+			return out
+		}
+		return v
+	}
+	return redact(values).(map[string]interface{}), redacted
+
+}
+
+// redact all leaf values
+func redactHelmValue(v interface{}) interface{} {
+
+	switch value := v.(type) {
+	case map[string]interface{}:
+		out := map[string]interface{}{}
+		for k, e := range value {
+			out[k] = redactHelmValue(e)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(value))
+		for i, e := range value {
+			out[i] = redactHelmValue(e)
+		}
+		return out
+	case nil:
+		return nil
+	}
+	return REDACTED_VALUE
+
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/kore3lab/dashboard/pkg/lang"
 
+	batchV1 "k8s.io/api/batch/v1"
 	"k8s.io/api/core/v1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -32,3 +33,57 @@ func GetJobPods(apiClient *kubernetes.Clientset, namespace string, name string)
 	return lang.FilterPodsByControllerRef(job, podList.Items), &job.Spec.Template.Spec, nil
 
 }
+
+// job's active, succeeded, failed count (namespace = "" : all namespaces)
+func GetJobsSummary(apiClient *kubernetes.Clientset, namespace string, options metaV1.ListOptions) (JobSummary, error) {
+
+	summary := JobSummary{}
+	list, err := apiClient.BatchV1().Jobs(namespace).List(context.TODO(), options)
+	if err != nil {
+		return summary, err
+	}
+	summary.Total = len(list.Items)
+	for _, m := range list.Items {
+		switch getJobStatus(m) {
+		case batchV1.JobComplete:
+			summary.Succeeded += 1
+		case batchV1.JobFailed:
+			summary.Failed += 1
+		default:
+			summary.Active += 1
+		}
+	}
+	return summary, nil
+
+}
+
+// cronjob's active, suspended count (namespace = "" : all namespaces)
+func GetCronJobsSummary(apiClient *kubernetes.Clientset, namespace string, options metaV1.ListOptions) (CronJobSummary, error) {
+
+	summary := CronJobSummary{}
+	list, err := apiClient.BatchV1().CronJobs(namespace).List(context.TODO(), options)
+	if err != nil {
+		return summary, err
+	}
+	summary.Total = len(list.Items)
+	for _, m := range list.Items {
+		if m.Spec.Suspend != nil && *m.Spec.Suspend {
+			summary.Suspended += 1
+		}
+		if len(m.Status.Active) > 0 {
+			summary.Active += 1
+		}
+	}
+	return summary, nil
+
+}
+
+// Complete, Failed or "" (running)
+func getJobStatus(job batchV1.Job) batchV1.JobConditionType {
+	for _, c := range job.Status.Conditions {
+		if (c.Type == batchV1.JobComplete || c.Type == batchV1.JobFailed) && c.Status == v1.ConditionTrue {
+			return c.Type
+		}
+	}
+	return ""
+}
@@ -0,0 +1,205 @@
+package model
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kore3lab/dashboard/pkg/client"
+	"github.com/kore3lab/dashboard/pkg/config"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// json-patch operation (RFC 6902)
+type jsonPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// a key-editable resource (configmap, secret)
+type keyedObject struct {
+	api      *client.DynamicClient
+	obj      *unstructured.Unstructured
+	resource string
+}
+
+func getKeyedObject(cluster string, namespace string, resource string, name string) (*keyedObject, error) {
+
+	if resource != "secrets" && resource != "configmaps" {
+		return nil, errors.NewBadRequest(fmt.Sprintf("unsupported resource '%s'", resource))
+	}
+
+	clientSet, err := config.Cluster.Client(cluster)
+	if err != nil {
+		return nil, err
+	}
+	api, err := clientSet.NewDynamicClientSchema("", "v1", resource)
+	if err != nil {
+		return nil, err
+	}
+	api.SetNamespace(namespace)
+	obj, err := api.GET(name, metaV1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &keyedObject{api: api, obj: obj, resource: resource}, nil
+
+}
+
+// field ("data" or "binaryData") that contains a key
+func (me *keyedObject) field(key string) string {
+	for _, f := range []string{"data", "binaryData"} {
+		if data, ok := me.obj.Object[f].(map[string]interface{}); ok {
+			if _, exist := data[key]; exist {
+				return f
+			}
+		}
+	}
+	return ""
+}
+
+// "test" resourceVersion (optimistic lock)
+func (me *keyedObject) newPatch() []jsonPatchOperation {
+	return []jsonPatchOperation{{Op: "test", Path: "/metadata/resourceVersion", Value: me.obj.GetResourceVersion()}}
+}
+
+// "add" a field if not exists
+func (me *keyedObject) ensureField(patch []jsonPatchOperation, field string, added map[string]bool) []jsonPatchOperation {
+	if _, exist := me.obj.Object[field]; !exist && !added[field] {
+		added[field] = true
+		patch = append(patch, jsonPatchOperation{Op: "add", Path: "/" + field, Value: map[string]interface{}{}})
+	}
+	return patch
+}
+
+// resourceVersion is changed after read
+func (me *keyedObject) modified() bool {
+	obj, err := me.api.GET(me.obj.GetName(), metaV1.GetOptions{})
+	return err != nil || obj.GetResourceVersion() != me.obj.GetResourceVersion()
+}
+
+func (me *keyedObject) apply(patch []jsonPatchOperation) (*unstructured.Unstructured, error) {
+
+	payload, err := json.Marshal(patch)
+	if err != nil {
+		return nil, err
+	}
+	r, err := me.api.PATCH(me.obj.GetName(), types.JSONPatchType, bytes.NewReader(payload), metaV1.PatchOptions{})
+	if errors.IsInvalid(err) && me.modified() {
+		// "test" resourceVersion failed (modified after read), other invalid errors (key, size) are returned as they are
+		return nil, keyError(http.StatusConflict, metaV1.StatusReasonConflict, "'%s' has been modified, reload and try again (cause=%s)", me.obj.GetName(), err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+	RedactObject(r)
+	return r, nil
+
+}
+
+// add or replace keys (values are raw bytes, base64 encoded automatically)
+func SetKeys(cluster string, namespace string, resource string, name string, values map[string][]byte, overwrite bool) (*unstructured.Unstructured, error) {
+
+	if len(values) == 0 {
+		return nil, errors.NewBadRequest("keys are empty")
+	}
+	for k := range values {
+		if errs := validation.IsConfigMapKey(k); len(errs) > 0 {
+			return nil, errors.NewBadRequest(fmt.Sprintf("invalid key '%s' (%s)", k, strings.Join(errs, ", ")))
+		}
+	}
+
+	o, err := getKeyedObject(cluster, namespace, resource, name)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := o.newPatch()
+	added := map[string]bool{}
+	for k, v := range values {
+		current := o.field(k)
+		if current != "" && !overwrite {
+			return nil, keyError(http.StatusConflict, metaV1.StatusReasonAlreadyExists, "key '%s' already exists in '%s'", k, name)
+		}
+
+		// secret : base64, configmap : utf-8 text ("data") or binary ("binaryData")
+		field := "data"
+		var value interface{} = string(v)
+		if o.resource == "secrets" {
+			value = base64.StdEncoding.EncodeToString(v)
+		} else if !utf8.Valid(v) {
+			field = "binaryData"
+			value = base64.StdEncoding.EncodeToString(v)
+		}
+
+		if current != "" && current != field {
+			patch = append(patch, jsonPatchOperation{Op: "remove", Path: keyPath(current, k)})
+		}
+		patch = o.ensureField(patch, field, added)
+		patch = append(patch, jsonPatchOperation{Op: "add", Path: keyPath(field, k), Value: value})
+	}
+
+	return o.apply(patch)
+
+}
+
+// rename a key
+func RenameKey(cluster string, namespace string, resource string, name string, key string, newKey string) (*unstructured.Unstructured, error) {
+
+	if errs := validation.IsConfigMapKey(newKey); len(errs) > 0 {
+		return nil, errors.NewBadRequest(fmt.Sprintf("invalid key '%s' (%s)", newKey, strings.Join(errs, ", ")))
+	}
+
+	o, err := getKeyedObject(cluster, namespace, resource, name)
+	if err != nil {
+		return nil, err
+	}
+	field := o.field(key)
+	if field == "" {
+		return nil, keyError(http.StatusNotFound, metaV1.StatusReasonNotFound, "key '%s' does not exist in '%s'", key, name)
+	}
+	if o.field(newKey) != "" {
+		return nil, keyError(http.StatusConflict, metaV1.StatusReasonAlreadyExists, "key '%s' already exists in '%s'", newKey, name)
+	}
+
+	patch := append(o.newPatch(), jsonPatchOperation{Op: "move", From: keyPath(field, key), Path: keyPath(field, newKey)})
+	return o.apply(patch)
+
+}
+
+// delete a key
+func DeleteKey(cluster string, namespace string, resource string, name string, key string) (*unstructured.Unstructured, error) {
+
+	o, err := getKeyedObject(cluster, namespace, resource, name)
+	if err != nil {
+		return nil, err
+	}
+	field := o.field(key)
+	if field == "" {
+		return nil, keyError(http.StatusNotFound, metaV1.StatusReasonNotFound, "key '%s' does not exist in '%s'", key, name)
+	}
+
+	patch := append(o.newPatch(), jsonPatchOperation{Op: "remove", Path: keyPath(field, key)})
+	return o.apply(patch)
+
+}
+
+// a status error with http status code
+func keyError(code int32, reason metaV1.StatusReason, format string, a ...interface{}) error {
+	return &errors.StatusError{ErrStatus: metaV1.Status{Status: metaV1.StatusFailure, Code: code, Reason: reason, Message: fmt.Sprintf(format, a...)}}
+}
+
+// json-pointer (RFC 6901) of a key
+func keyPath(field string, key string) string {
+	return fmt.Sprintf("/%s/%s", field, strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1"))
+}
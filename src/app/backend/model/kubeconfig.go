@@ -0,0 +1,221 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/kore3lab/dashboard/pkg/config"
+	"github.com/kore3lab/dashboard/pkg/lang"
+	authenticationV1 "k8s.io/api/authentication/v1"
+	coreV1 "k8s.io/api/core/v1"
+	rbacV1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdApi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	MANAGED_BY_LABEL              = "app.kubernetes.io/managed-by"
+	MANAGED_BY_VALUE              = "kore-board"
+	defaultTokenExpirationSeconds = 3600
+	minTokenExpirationSeconds     = 600
+	maxTokenExpirationSeconds     = 24 * 3600
+)
+
+type ServiceAccountKubeconfigRequest struct {
+	Namespace         string `json:"namespace"`
+	Name              string `json:"name"`
+	RoleKind          string `json:"roleKind"` // "Role" or "ClusterRole"
+	RoleName          string `json:"roleName"`
+	ClusterWide       bool   `json:"clusterWide"`       // bind a cluster-role by a ClusterRoleBinding (default: RoleBinding in the namespace)
+	ExpirationSeconds int64  `json:"expirationSeconds"` // token expiration (default: 3600, min: 600, max: 86400)
+}
+
+type Kubeconfig struct {
+	Kubeconfig          string      `json:"kubeconfig"`
+	ExpirationTimestamp metaV1.Time `json:"expirationTimestamp"`
+}
+
+// create (or reuse) a service account & a binding and returns a kubeconfig with a time-bound token
+func CreateServiceAccountKubeconfig(cluster string, req ServiceAccountKubeconfigRequest) (*Kubeconfig, error) {
+
+	if req.Namespace == "" || req.Name == "" {
+		return nil, fmt.Errorf("namespace and name are required")
+	}
+	if req.RoleName != "" && req.RoleKind != ELEMENT_KIND_ROLE && req.RoleKind != ELEMENT_KIND_CLUSTER_ROLE {
+		return nil, fmt.Errorf("unsupported role kind '%s'", req.RoleKind)
+	}
+	if req.ClusterWide && req.RoleKind == ELEMENT_KIND_ROLE {
+		return nil, fmt.Errorf("a role can't be bound cluster-wide")
+	}
+	if req.ExpirationSeconds == 0 {
+		req.ExpirationSeconds = defaultTokenExpirationSeconds
+	} else if req.ExpirationSeconds < minTokenExpirationSeconds {
+		return nil, fmt.Errorf("expirationSeconds must be greater than or equal to %d", minTokenExpirationSeconds)
+	} else if req.ExpirationSeconds > maxTokenExpirationSeconds {
+		return nil, fmt.Errorf("expirationSeconds must be less than or equal to %d", maxTokenExpirationSeconds)
+	}
+
+	client, err := config.Cluster.Client(cluster)
+	if err != nil {
+		return nil, err
+	}
+	api, err := client.NewKubernetesClient()
+	if err != nil {
+		return nil, err
+	}
+
+	// service account
+	if _, err := api.CoreV1().ServiceAccounts(req.Namespace).Get(context.TODO(), req.Name, metaV1.GetOptions{}); errors.IsNotFound(err) {
+		sa := &coreV1.ServiceAccount{ObjectMeta: metaV1.ObjectMeta{Name: req.Name, Namespace: req.Namespace, Labels: map[string]string{MANAGED_BY_LABEL: MANAGED_BY_VALUE}}}
+		if _, err := api.CoreV1().ServiceAccounts(req.Namespace).Create(context.TODO(), sa, metaV1.CreateOptions{}); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	// binding
+	if req.RoleName != "" {
+		if err := bindServiceAccount(api, req); err != nil {
+			return nil, err
+		}
+	}
+
+	// token
+	token, err := api.CoreV1().ServiceAccounts(req.Namespace).CreateToken(context.TODO(), req.Name, &authenticationV1.TokenRequest{
+		Spec: authenticationV1.TokenRequestSpec{ExpirationSeconds: &req.ExpirationSeconds},
+	}, metaV1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := newKubeconfig(cluster, fmt.Sprintf("%s-%s", req.Namespace, req.Name), &clientcmdApi.AuthInfo{Token: token.Status.Token}, req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Kubeconfig{Kubeconfig: string(b), ExpirationTimestamp: token.Status.ExpirationTimestamp}, nil
+
+}
+
+// create (or reuse) a RoleBinding or a ClusterRoleBinding
+func bindServiceAccount(api *kubernetes.Clientset, req ServiceAccountKubeconfigRequest) error {
+
+	name := strings.ToLower(fmt.Sprintf("%s-%s", req.Name, strings.ReplaceAll(req.RoleName, ":", "-")))
+	if req.ClusterWide {
+		name = fmt.Sprintf("%s-%s", req.Namespace, name)
+	}
+	subject := rbacV1.Subject{Kind: rbacV1.ServiceAccountKind, Name: req.Name, Namespace: req.Namespace}
+	roleRef := rbacV1.RoleRef{APIGroup: rbacV1.GroupName, Kind: req.RoleKind, Name: req.RoleName}
+	meta := metaV1.ObjectMeta{Name: name, Labels: map[string]string{MANAGED_BY_LABEL: MANAGED_BY_VALUE}}
+
+	// returns subjects to update (nil = no changes)
+	merge := func(ref rbacV1.RoleRef, subjects []rbacV1.Subject) ([]rbacV1.Subject, error) {
+		if ref.Kind != roleRef.Kind || ref.Name != roleRef.Name {
+			return nil, fmt.Errorf("binding '%s' already exists with a different role '%s/%s'", name, ref.Kind, ref.Name)
+		}
+		for _, s := range subjects {
+			if s.Kind == subject.Kind && s.Name == subject.Name && s.Namespace == subject.Namespace {
+				return nil, nil
+			}
+		}
+		return append(subjects, subject), nil
+	}
+
+	if req.ClusterWide {
+		binding, err := api.RbacV1().ClusterRoleBindings().Get(context.TODO(), name, metaV1.GetOptions{})
+		if errors.IsNotFound(err) {
+			_, err = api.RbacV1().ClusterRoleBindings().Create(context.TODO(), &rbacV1.ClusterRoleBinding{ObjectMeta: meta, RoleRef: roleRef, Subjects: []rbacV1.Subject{subject}}, metaV1.CreateOptions{})
+			return err
+		} else if err != nil {
+			return err
+		}
+		subjects, err := merge(binding.RoleRef, binding.Subjects)
+		if err != nil || subjects == nil {
+			return err
+		}
+		binding.Subjects = subjects
+		_, err = api.RbacV1().ClusterRoleBindings().Update(context.TODO(), binding, metaV1.UpdateOptions{})
+		return err
+	}
+
+	meta.Namespace = req.Namespace
+	binding, err := api.RbacV1().RoleBindings(req.Namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err = api.RbacV1().RoleBindings(req.Namespace).Create(context.TODO(), &rbacV1.RoleBinding{ObjectMeta: meta, RoleRef: roleRef, Subjects: []rbacV1.Subject{subject}}, metaV1.CreateOptions{})
+		return err
+	} else if err != nil {
+		return err
+	}
+	subjects, err := merge(binding.RoleRef, binding.Subjects)
+	if err != nil || subjects == nil {
+		return err
+	}
+	binding.Subjects = subjects
+	_, err = api.RbacV1().RoleBindings(req.Namespace).Update(context.TODO(), binding, metaV1.UpdateOptions{})
+	return err
+
+}
+
+// cluster entry of a context (in-cluster : rest config)
+func getClusterEntry(cluster string) (*clientcmdApi.Cluster, error) {
+
+	if config.Cluster.KubeConfig != nil {
+		if ctx := config.Cluster.KubeConfig.Contexts[cluster]; ctx != nil {
+			if c := config.Cluster.KubeConfig.Clusters[ctx.Cluster]; c != nil {
+				entry := c.DeepCopy()
+				entry.LocationOfOrigin = ""
+				// embed certificate-authority file
+				if entry.CertificateAuthority != "" && len(entry.CertificateAuthorityData) == 0 {
+					if ca, err := ioutil.ReadFile(entry.CertificateAuthority); err == nil {
+						entry.CertificateAuthorityData = ca
+						entry.CertificateAuthority = ""
+					}
+				}
+				return entry, nil
+			}
+		}
+	}
+
+	clientSet, err := config.Cluster.Client(cluster)
+	if err != nil {
+		return nil, err
+	}
+	entry := &clientcmdApi.Cluster{
+		Server:                   clientSet.RESTConfig.Host,
+		CertificateAuthorityData: clientSet.RESTConfig.CAData,
+		InsecureSkipTLSVerify:    clientSet.RESTConfig.Insecure,
+	}
+	if len(entry.CertificateAuthorityData) == 0 && clientSet.RESTConfig.CAFile != "" {
+		if entry.CertificateAuthorityData, err = ioutil.ReadFile(clientSet.RESTConfig.CAFile); err != nil {
+			return nil, err
+		}
+	}
+	return entry, nil
+
+}
+
+// a kubeconfig (yaml) of a cluster and a user
+func newKubeconfig(cluster string, user string, authInfo *clientcmdApi.AuthInfo, namespace string) ([]byte, error) {
+
+	entry, err := getClusterEntry(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	name := lang.NVL(cluster, config.Cluster.DefaultContext)
+	contextName := fmt.Sprintf("%s@%s", user, name)
+	kubeconfig := clientcmdApi.NewConfig()
+	kubeconfig.Clusters[name] = entry
+	kubeconfig.AuthInfos[user] = authInfo
+	kubeconfig.Contexts[contextName] = &clientcmdApi.Context{Cluster: name, AuthInfo: user, Namespace: namespace}
+	kubeconfig.CurrentContext = contextName
+
+	return clientcmd.Write(*kubeconfig)
+
+}
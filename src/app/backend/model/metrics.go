@@ -9,6 +9,7 @@ import (
 	"github.com/kore3lab/dashboard/pkg/config"
 	coreV1 "k8s.io/api/core/v1"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 )
 
 // get cluster metrics
@@ -84,7 +85,7 @@ func GetNodeCumulativeMetrics(cluster string, name string) (*NodeCumulativeMetri
 	return &result, nil
 }
 
-// get workloads metrics
+// get workloads metrics (pods, deployments, statefulsets, daemonsets, replicasets, jobs, cronjobs)
 func GetWorkloadCumulativeMetrics(cluster string, namespace string, resource string, name string) (*CumulativeMetrics, error) {
 
 	clientSet, err := config.Cluster.Client(cluster)
@@ -97,37 +98,9 @@ func GetWorkloadCumulativeMetrics(cluster string, namespace string, resource str
 		return nil, err
 	}
 
-	var pods []coreV1.Pod
-	var podSpec *coreV1.PodSpec
-	if resource == "pods" {
-		pod, err := apiClient.CoreV1().Pods(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
-		if err != nil {
-			return nil, err
-		}
-		pods = []coreV1.Pod{*pod}
-		podSpec = &pod.Spec
-	} else if resource == "deployments" {
-		pods, podSpec, err = GetDeploymentPods(apiClient, namespace, name)
-		if err != nil {
-			return nil, err
-		}
-	} else if resource == "statefulsets" {
-		pods, podSpec, err = GetStatefulSetPods(apiClient, namespace, name)
-		if err != nil {
-			return nil, err
-		}
-	} else if resource == "daemonsets" {
-		pods, podSpec, err = GetDaemonSetPods(apiClient, namespace, name)
-		if err != nil {
-			return nil, err
-		}
-	} else if resource == "replicasets" {
-		pods, podSpec, err = GetReplicaSetPods(apiClient, namespace, name)
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		return nil, errors.New(fmt.Sprintf("unsupported resource '%s'", resource))
+	pods, podSpec, err := getWorkloadPods(apiClient, namespace, resource, name)
+	if err != nil {
+		return nil, err
 	}
 	if len(pods) > 0 {
 		names := []string{}
@@ -166,6 +139,32 @@ func GetWorkloadCumulativeMetrics(cluster string, namespace string, resource str
 
 }
 
+// get pods and a pod spec of a workload (pods, deployments, statefulsets, daemonsets, replicasets, jobs, cronjobs)
+func getWorkloadPods(apiClient *kubernetes.Clientset, namespace string, resource string, name string) ([]coreV1.Pod, *coreV1.PodSpec, error) {
+
+	if resource == "pods" {
+		pod, err := apiClient.CoreV1().Pods(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+		return []coreV1.Pod{*pod}, &pod.Spec, nil
+	} else if resource == "deployments" {
+		return GetDeploymentPods(apiClient, namespace, name)
+	} else if resource == "statefulsets" {
+		return GetStatefulSetPods(apiClient, namespace, name)
+	} else if resource == "daemonsets" {
+		return GetDaemonSetPods(apiClient, namespace, name)
+	} else if resource == "replicasets" {
+		return GetReplicaSetPods(apiClient, namespace, name)
+	} else if resource == "jobs" {
+		return GetJobPods(apiClient, namespace, name)
+	} else if resource == "cronjobs" {
+		return GetCronJobPods(apiClient, namespace, name)
+	}
+	return nil, nil, errors.New(fmt.Sprintf("unsupported resource '%s'", resource))
+
+}
+
 // get pod list with metrics
 func GetNodePodListWithMetrics(cluster string, name string) (interface{}, error) {
 
@@ -241,6 +240,11 @@ func GetWorkloadPodListWithMetrics(cluster string, namespace string, resource st
 		if err != nil {
 			return nil, err
 		}
+	} else if resource == "cronjobs" {
+		pods, _, err = GetCronJobPods(apiClient, namespace, name)
+		if err != nil {
+			return nil, err
+		}
 	} else {
 		return nil, errors.New(fmt.Sprintf("unsupported resource '%s'", resource))
 	}
@@ -0,0 +1,557 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kore3lab/dashboard/pkg/config"
+	"github.com/kore3lab/dashboard/pkg/lang"
+	coreV1 "k8s.io/api/core/v1"
+	networkV1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// a graph is limited to this number of pods (select a namespace)
+const maxNetworkPolicyGraphPods = 1000
+
+// verdict of a direction (egress of a source pod, ingress of a destination pod)
+type NetworkPolicyVerdict struct {
+	Direction string   `json:"direction"` // Ingress, Egress
+	Isolated  bool     `json:"isolated"`  // selected by policies of the direction
+	Allowed   bool     `json:"allowed"`
+	Policies  []string `json:"policies"`        // policies selecting the pod ("namespace/name")
+	AllowedBy []string `json:"allowedBy"`       // policies with a rule allowing the traffic
+	Ports     []string `json:"ports,omitempty"` // ports of allowing rules ("TCP/80", "*" : all ports)
+}
+
+// can A talk to B on port P
+type Reachability struct {
+	From     string               `json:"from"`
+	To       string               `json:"to"`
+	Port     int32                `json:"port"` // 0 : unspecified
+	Protocol string               `json:"protocol"`
+	Allowed  bool                 `json:"allowed"`
+	Egress   NetworkPolicyVerdict `json:"egress"`
+	Ingress  NetworkPolicyVerdict `json:"ingress"`
+	Message  string               `json:"message"`
+}
+
+// a pod or an external ip (pod = nil)
+type networkPolicyPeer struct {
+	pod *coreV1.Pod
+	ip  net.IP
+}
+
+// objects to evaluate network policies (parsed selectors & policies selecting a pod are cached)
+type networkPolicies struct {
+	namespaces        map[string]labels.Set
+	pods              []coreV1.Pod
+	policies          []networkV1.NetworkPolicy
+	selectors         map[*metaV1.LabelSelector]labels.Selector
+	selectingPolicies map[string][]*networkV1.NetworkPolicy
+}
+
+// reachability graph of pods (pods -> reachable pods, ipBlocks), line : allowed ports
+//   - ports : container ports of a destination pod (no container ports : ports of allowing rules)
+//   - pods not isolated in both directions are collapsed into an "any" node of a namespace (pods not isolated for ingress)
+func GetNetworkPolicyGraph(cluster string, namespace string) (Hierarchy, error) {
+
+	np, err := getNetworkPolicies(cluster, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if len(np.pods) > maxNetworkPolicyGraphPods {
+		return nil, errors.NewBadRequest(fmt.Sprintf("too many pods (%d > %d), select a namespace", len(np.pods), maxNetworkPolicyGraphPods))
+	}
+
+	hierarchy := make(map[string][]HierarchyNode)
+	for ns := range np.namespaces {
+		if namespace == "" || ns == namespace {
+			hierarchy[ns] = []HierarchyNode{}
+		}
+	}
+
+	// "any" nodes : pods not isolated for ingress (by namespace)
+	unisolated := map[string]int{}
+	for i := range np.pods {
+		if len(np.selecting(&np.pods[i], networkV1.PolicyTypeIngress)) == 0 {
+			unisolated[np.pods[i].Namespace]++
+		}
+	}
+	for ns, count := range unisolated {
+		hierarchy[ns] = append(hierarchy[ns], newAnyPodNode(ns, count, ""))
+	}
+
+	for i := range np.pods {
+		src := &np.pods[i]
+		hierarchy[src.Namespace] = append(hierarchy[src.Namespace], newHierarchyNode(metaV1.TypeMeta{APIVersion: "v1", Kind: ELEMENT_KIND_POD}, src.ObjectMeta, ""))
+		egressIsolated := len(np.selecting(src, networkV1.PolicyTypeEgress)) > 0
+
+		// pod -> pods
+		anyNamespaces := map[string]bool{}
+		for j := range np.pods {
+			dst := &np.pods[j]
+			if i == j {
+				continue
+			}
+			if !egressIsolated && len(np.selecting(dst, networkV1.PolicyTypeIngress)) == 0 {
+				anyNamespaces[dst.Namespace] = true
+				continue
+			}
+			ports := []string{}
+			for _, p := range containerPorts(dst) {
+				if egress, ingress := np.evaluate(newNetworkPolicyPeer(src), newNetworkPolicyPeer(dst), p.ContainerPort, p.Protocol); egress.Allowed && ingress.Allowed {
+					if p.ContainerPort == 0 {
+						ports = append(ports, networkPolicyAllowedPorts(egress, ingress)...)
+					} else {
+						ports = append(ports, networkPolicyPortLabel(p.Protocol, p.ContainerPort))
+					}
+				}
+			}
+			if len(ports) > 0 {
+				n := newHierarchyNode(metaV1.TypeMeta{APIVersion: "v1", Kind: ELEMENT_KIND_POD}, dst.ObjectMeta, string(src.UID))
+				n.Line = strings.Join(uniqueStrings(ports), ",")
+				hierarchy[src.Namespace] = append(hierarchy[src.Namespace], n)
+			}
+		}
+
+		// pod -> "any" nodes
+		namespaces := []string{}
+		for ns := range anyNamespaces {
+			namespaces = append(namespaces, ns)
+		}
+		sort.Strings(namespaces)
+		for _, ns := range namespaces {
+			n := newAnyPodNode(ns, unisolated[ns], string(src.UID))
+			n.Line = "*"
+			hierarchy[src.Namespace] = append(hierarchy[src.Namespace], n)
+		}
+	}
+
+	// ipBlocks -> pods (ingress), pods -> ipBlocks (egress)
+	for i := range np.policies {
+		policy := &np.policies[i]
+		for _, pod := range np.selected(policy) {
+			if networkPolicyHasType(policy, networkV1.PolicyTypeIngress) {
+				for _, rule := range policy.Spec.Ingress {
+					for _, peer := range rule.From {
+						if peer.IPBlock != nil {
+							block := newIPBlockNode(pod.Namespace, peer.IPBlock, "")
+							hierarchy[pod.Namespace] = appendHierarchyNode(hierarchy[pod.Namespace], block)
+							n := newHierarchyNode(metaV1.TypeMeta{APIVersion: "v1", Kind: ELEMENT_KIND_POD}, pod.ObjectMeta, block.UID)
+							n.Line = networkPolicyPortsLabel(rule.Ports)
+							hierarchy[pod.Namespace] = appendHierarchyNode(hierarchy[pod.Namespace], n)
+						}
+					}
+				}
+			}
+			if networkPolicyHasType(policy, networkV1.PolicyTypeEgress) {
+				for _, rule := range policy.Spec.Egress {
+					for _, peer := range rule.To {
+						if peer.IPBlock != nil {
+							block := newIPBlockNode(pod.Namespace, peer.IPBlock, string(pod.UID))
+							block.Line = networkPolicyPortsLabel(rule.Ports)
+							hierarchy[pod.Namespace] = appendHierarchyNode(hierarchy[pod.Namespace], block)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return hierarchy, nil
+
+}
+
+// can A talk to B on port P (from, to : "namespace/pod" or an ip address, port 0 : unspecified)
+func GetReachability(cluster string, from string, to string, port int32, protocol string) (*Reachability, error) {
+
+	np, err := getNetworkPolicies(cluster, "")
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := np.peer(from)
+	if err != nil {
+		return nil, err
+	}
+	dst, err := np.peer(to)
+	if err != nil {
+		return nil, err
+	}
+	if src.pod == nil && dst.pod == nil {
+		return nil, errors.NewBadRequest("either 'from' or 'to' must be a pod")
+	}
+	if protocol == "" {
+		protocol = string(coreV1.ProtocolTCP)
+	}
+
+	result := &Reachability{From: from, To: to, Port: port, Protocol: strings.ToUpper(protocol)}
+	result.Egress, result.Ingress = np.evaluate(src, dst, port, coreV1.Protocol(result.Protocol))
+	result.Allowed = result.Egress.Allowed && result.Ingress.Allowed
+
+	explain := func(v NetworkPolicyVerdict) string {
+		if !v.Isolated {
+			return fmt.Sprintf("%s is not isolated (no policies select the pod)", v.Direction)
+		} else if v.Allowed {
+			return fmt.Sprintf("%s is allowed by %s", v.Direction, strings.Join(v.AllowedBy, ", "))
+		}
+		return fmt.Sprintf("%s is blocked, no rules of %s allow the traffic", v.Direction, strings.Join(v.Policies, ", "))
+	}
+	if result.Allowed {
+		result.Message = fmt.Sprintf("allowed : %s; %s", explain(result.Egress), explain(result.Ingress))
+	} else {
+		result.Message = "blocked :"
+		for _, v := range []NetworkPolicyVerdict{result.Egress, result.Ingress} {
+			if !v.Allowed {
+				result.Message = fmt.Sprintf("%s %s;", result.Message, explain(v))
+			}
+		}
+		result.Message = strings.TrimSuffix(result.Message, ";")
+	}
+
+	return result, nil
+
+}
+
+func getNetworkPolicies(cluster string, namespace string) (*networkPolicies, error) {
+
+	client, err := config.Cluster.Client(cluster)
+	if err != nil {
+		return nil, err
+	}
+	api, err := client.NewKubernetesClient()
+	if err != nil {
+		return nil, err
+	}
+
+	np := &networkPolicies{namespaces: map[string]labels.Set{}, pods: []coreV1.Pod{}, selectors: map[*metaV1.LabelSelector]labels.Selector{}, selectingPolicies: map[string][]*networkV1.NetworkPolicy{}}
+
+	// namespace labels (namespaceSelector)
+	nsList, err := api.CoreV1().Namespaces().List(context.TODO(), metaV1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, ns := range nsList.Items {
+		np.namespaces[ns.Name] = labels.Set(ns.Labels)
+	}
+
+	// running pods
+	podList, err := api.CoreV1().Pods(namespace).List(context.TODO(), metaV1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, pod := range podList.Items {
+		if pod.Status.Phase != coreV1.PodSucceeded && pod.Status.Phase != coreV1.PodFailed {
+			np.pods = append(np.pods, pod)
+		}
+	}
+
+	policyList, err := api.NetworkingV1().NetworkPolicies(namespace).List(context.TODO(), metaV1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	np.policies = policyList.Items
+
+	return np, nil
+
+}
+
+// "namespace/pod" or an ip address (a pod ip is resolved to the pod, otherwise an external peer)
+func (me *networkPolicies) peer(s string) (networkPolicyPeer, error) {
+
+	if ip := net.ParseIP(s); ip != nil {
+		for i := range me.pods {
+			if me.pods[i].Spec.HostNetwork {
+				continue // node ip (shared by host-network pods)
+			}
+			for _, podIP := range me.pods[i].Status.PodIPs {
+				if ip.Equal(net.ParseIP(podIP.IP)) {
+					return newNetworkPolicyPeer(&me.pods[i]), nil
+				}
+			}
+			if ip.Equal(net.ParseIP(me.pods[i].Status.PodIP)) {
+				return newNetworkPolicyPeer(&me.pods[i]), nil
+			}
+		}
+		return networkPolicyPeer{ip: ip}, nil
+	}
+	ns, name := "default", s
+	if i := strings.Index(s, "/"); i > 0 {
+		ns, name = s[:i], s[i+1:]
+	}
+	for i := range me.pods {
+		if me.pods[i].Namespace == ns && me.pods[i].Name == name {
+			return newNetworkPolicyPeer(&me.pods[i]), nil
+		}
+	}
+	return networkPolicyPeer{}, errors.NewBadRequest(fmt.Sprintf("unable to find a running pod '%s' (namespace/pod or an ip address)", s))
+
+}
+
+func newNetworkPolicyPeer(pod *coreV1.Pod) networkPolicyPeer {
+	return networkPolicyPeer{pod: pod, ip: net.ParseIP(pod.Status.PodIP)}
+}
+
+// verdicts of egress (source) and ingress (destination), an external peer is not isolated
+func (me *networkPolicies) evaluate(src networkPolicyPeer, dst networkPolicyPeer, port int32, protocol coreV1.Protocol) (NetworkPolicyVerdict, NetworkPolicyVerdict) {
+
+	egress := NetworkPolicyVerdict{Direction: string(networkV1.PolicyTypeEgress), Allowed: true, Policies: []string{}, AllowedBy: []string{}}
+	ingress := NetworkPolicyVerdict{Direction: string(networkV1.PolicyTypeIngress), Allowed: true, Policies: []string{}, AllowedBy: []string{}}
+
+	if src.pod != nil {
+		for _, policy := range me.selecting(src.pod, networkV1.PolicyTypeEgress) {
+			egress.Policies = append(egress.Policies, policy.Namespace+"/"+policy.Name)
+			allowed := false
+			for _, rule := range policy.Spec.Egress {
+				if me.peersMatch(policy.Namespace, rule.To, dst) && networkPolicyPortsMatch(rule.Ports, port, protocol, dst.pod) {
+					allowed = true
+					egress.Ports = append(egress.Ports, networkPolicyPortsLabel(rule.Ports))
+				}
+			}
+			if allowed {
+				egress.AllowedBy = append(egress.AllowedBy, policy.Namespace+"/"+policy.Name)
+			}
+		}
+		egress.Isolated = len(egress.Policies) > 0
+		egress.Allowed = !egress.Isolated || len(egress.AllowedBy) > 0
+	}
+
+	if dst.pod != nil {
+		for _, policy := range me.selecting(dst.pod, networkV1.PolicyTypeIngress) {
+			ingress.Policies = append(ingress.Policies, policy.Namespace+"/"+policy.Name)
+			allowed := false
+			for _, rule := range policy.Spec.Ingress {
+				if me.peersMatch(policy.Namespace, rule.From, src) && networkPolicyPortsMatch(rule.Ports, port, protocol, dst.pod) {
+					allowed = true
+					ingress.Ports = append(ingress.Ports, networkPolicyPortsLabel(rule.Ports))
+				}
+			}
+			if allowed {
+				ingress.AllowedBy = append(ingress.AllowedBy, policy.Namespace+"/"+policy.Name)
+			}
+		}
+		ingress.Isolated = len(ingress.Policies) > 0
+		ingress.Allowed = !ingress.Isolated || len(ingress.AllowedBy) > 0
+	}
+
+	return egress, ingress
+
+}
+
+// policies of a type selecting a pod (cached by pod)
+func (me *networkPolicies) selecting(pod *coreV1.Pod, policyType networkV1.PolicyType) []*networkV1.NetworkPolicy {
+	key := string(pod.UID) + "/" + string(policyType)
+	if policies, ok := me.selectingPolicies[key]; ok {
+		return policies
+	}
+	policies := []*networkV1.NetworkPolicy{}
+	for i := range me.policies {
+		policy := &me.policies[i]
+		if policy.Namespace == pod.Namespace && networkPolicyHasType(policy, policyType) && me.matches(&policy.Spec.PodSelector, pod.Labels) {
+			policies = append(policies, policy)
+		}
+	}
+	me.selectingPolicies[key] = policies
+	return policies
+}
+
+// pods selected by a policy
+func (me *networkPolicies) selected(policy *networkV1.NetworkPolicy) []coreV1.Pod {
+	pods := []coreV1.Pod{}
+	for _, pod := range me.pods {
+		if pod.Namespace == policy.Namespace && me.matches(&policy.Spec.PodSelector, pod.Labels) {
+			pods = append(pods, pod)
+		}
+	}
+	return pods
+}
+
+// a label selector matches (parsed once)
+func (me *networkPolicies) matches(selector *metaV1.LabelSelector, set labels.Set) bool {
+	s, ok := me.selectors[selector]
+	if !ok {
+		var err error
+		if s, err = metaV1.LabelSelectorAsSelector(selector); err != nil {
+			s = labels.Nothing()
+		}
+		me.selectors[selector] = s
+	}
+	return s.Matches(set)
+}
+
+// peers of a rule match (empty : all peers)
+func (me *networkPolicies) peersMatch(namespace string, peers []networkV1.NetworkPolicyPeer, peer networkPolicyPeer) bool {
+
+	if len(peers) == 0 {
+		return true
+	}
+	for _, p := range peers {
+		if p.IPBlock != nil {
+			if peer.ip != nil && ipBlockContains(p.IPBlock, peer.ip) {
+				return true
+			}
+		} else if peer.pod != nil {
+			nsMatched := peer.pod.Namespace == namespace
+			if p.NamespaceSelector != nil {
+				nsMatched = me.matches(p.NamespaceSelector, me.namespaces[peer.pod.Namespace])
+			}
+			if nsMatched && (p.PodSelector == nil || me.matches(p.PodSelector, peer.pod.Labels)) {
+				return true
+			}
+		}
+	}
+	return false
+
+}
+
+// policy types (default : Ingress, Egress if egress rules exist)
+func networkPolicyHasType(policy *networkV1.NetworkPolicy, policyType networkV1.PolicyType) bool {
+	if len(policy.Spec.PolicyTypes) == 0 {
+		return policyType == networkV1.PolicyTypeIngress || len(policy.Spec.Egress) > 0
+	}
+	for _, t := range policy.Spec.PolicyTypes {
+		if t == policyType {
+			return true
+		}
+	}
+	return false
+}
+
+// ports of a rule match (empty : all ports, port 0 : unspecified, any port of a rule), named ports are resolved by container ports of a destination pod
+func networkPolicyPortsMatch(ports []networkV1.NetworkPolicyPort, port int32, protocol coreV1.Protocol, pod *coreV1.Pod) bool {
+
+	if len(ports) == 0 {
+		return true
+	}
+	for _, p := range ports {
+		if (p.Protocol == nil && protocol != coreV1.ProtocolTCP) || (p.Protocol != nil && *p.Protocol != protocol) {
+			continue
+		}
+		if p.Port == nil {
+			return true
+		} else if port == 0 {
+			return true
+		} else if p.Port.Type == intstr.Int {
+			if port == p.Port.IntVal || (p.EndPort != nil && port >= p.Port.IntVal && port <= *p.EndPort) {
+				return true
+			}
+		} else if pod != nil {
+			for _, c := range containerPorts(pod) {
+				if c.Name == p.Port.StrVal && c.ContainerPort == port && c.Protocol == protocol {
+					return true
+				}
+			}
+		}
+	}
+	return false
+
+}
+
+// container ports of a pod (no ports : an unspecified port)
+func containerPorts(pod *coreV1.Pod) []coreV1.ContainerPort {
+	ports := []coreV1.ContainerPort{}
+	for _, c := range pod.Spec.Containers {
+		for _, p := range c.Ports {
+			if p.Protocol == "" {
+				p.Protocol = coreV1.ProtocolTCP
+			}
+			ports = append(ports, p)
+		}
+	}
+	if len(ports) == 0 {
+		ports = append(ports, coreV1.ContainerPort{Protocol: coreV1.ProtocolTCP})
+	}
+	return ports
+}
+
+func ipBlockContains(block *networkV1.IPBlock, ip net.IP) bool {
+	if _, cidr, err := net.ParseCIDR(block.CIDR); err != nil || !cidr.Contains(ip) {
+		return false
+	}
+	for _, except := range block.Except {
+		if _, cidr, err := net.ParseCIDR(except); err == nil && cidr.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func newIPBlockNode(namespace string, block *networkV1.IPBlock, owner string) HierarchyNode {
+	n := HierarchyNode{UID: "ipblock:" + block.CIDR, Name: block.CIDR, Kind: ELEMENT_KIND_IPBLOCK, Namespace: namespace, Owner: owner}
+	if len(block.Except) > 0 {
+		n.Attributes = map[string]string{"except": strings.Join(block.Except, ",")}
+	}
+	return n
+}
+
+// pods not isolated for ingress in a namespace
+func newAnyPodNode(namespace string, count int, owner string) HierarchyNode {
+	return HierarchyNode{UID: "any:" + namespace, Name: "*", Kind: ELEMENT_KIND_ANY, Namespace: namespace, Owner: owner, Attributes: map[string]string{"pods": strconv.Itoa(count)}}
+}
+
+// append a node if not exists (same uid & owner)
+func appendHierarchyNode(nodes []HierarchyNode, n HierarchyNode) []HierarchyNode {
+	for _, m := range nodes {
+		if m.UID == n.UID && m.Owner == n.Owner {
+			return nodes
+		}
+	}
+	return append(nodes, n)
+}
+
+func networkPolicyPortLabel(protocol coreV1.Protocol, port int32) string {
+	if port == 0 {
+		return "*"
+	}
+	return fmt.Sprintf("%s/%d", protocol, port)
+}
+
+// ports of allowing rules for an unspecified port (the isolated direction, ingress first)
+func networkPolicyAllowedPorts(egress NetworkPolicyVerdict, ingress NetworkPolicyVerdict) []string {
+	if ingress.Isolated {
+		return ingress.Ports
+	} else if egress.Isolated {
+		return egress.Ports
+	}
+	return []string{"*"}
+}
+
+func uniqueStrings(arr []string) []string {
+	unique := []string{}
+	for _, s := range arr {
+		if !lang.ArrayContains(unique, s) {
+			unique = append(unique, s)
+		}
+	}
+	return unique
+}
+
+// "TCP/80,TCP/8000-8080,TCP/http" ("*" : all ports)
+func networkPolicyPortsLabel(ports []networkV1.NetworkPolicyPort) string {
+	if len(ports) == 0 {
+		return "*"
+	}
+	s := []string{}
+	for _, p := range ports {
+		protocol := string(coreV1.ProtocolTCP)
+		if p.Protocol != nil {
+			protocol = string(*p.Protocol)
+		}
+		if p.Port == nil {
+			s = append(s, protocol+"/*")
+		} else if p.EndPort != nil {
+			s = append(s, fmt.Sprintf("%s/%s-%d", protocol, p.Port.String(), *p.EndPort))
+		} else {
+			s = append(s, fmt.Sprintf("%s/%s", protocol, p.Port.String()))
+		}
+	}
+	return strings.Join(s, ",")
+}
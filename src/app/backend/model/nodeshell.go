@@ -0,0 +1,85 @@
+package model
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kore3lab/dashboard/pkg/config"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const nodeShellGrantSeconds = 60
+
+// a signed grant to open a node shell on the terminal service ("grant" query parameter of termtype "node")
+type NodeShellGrant struct {
+	Grant     string      `json:"grant"`
+	ExpiredAt metaV1.Time `json:"expiredAt"`
+}
+
+// claims of a grant : base64url(json) "." base64url(hmac-sha256) signed by the "node-shell-key" shared with the terminal service
+type nodeShellClaims struct {
+	Cluster   string `json:"cluster"`
+	Node      string `json:"node"`
+	User      string `json:"user"`
+	ExpiredAt int64  `json:"expiredAt"`
+}
+
+// issue a node shell grant for a signed-in user listed in "node-shell-users" (the context should have an admin role)
+func CreateNodeShellGrant(cluster string, node string, user string) (*NodeShellGrant, error) {
+
+	if config.Value.NodeShellKey == "" {
+		return nil, errors.NewForbidden(schema.GroupResource{Resource: "nodes"}, node, fmt.Errorf("node shell is disabled (startup parameter 'node-shell-key' is empty)"))
+	}
+	allowed := false
+	for _, u := range config.Value.NodeShellUsers {
+		if user != "" && u == user {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, errors.NewForbidden(schema.GroupResource{Resource: "nodes"}, node, fmt.Errorf("node shell is not allowed for user '%s' (startup parameter 'node-shell-users')", user))
+	}
+
+	reviews, err := ReviewAccess(cluster, AccessReviewRequest{Attributes: []AccessAttributes{{Verb: "*", Group: "*", Resource: "*"}}})
+	if err != nil {
+		return nil, err
+	}
+	if len(reviews) == 0 || !reviews[0].Allowed {
+		return nil, errors.NewForbidden(schema.GroupResource{Resource: "nodes"}, node, fmt.Errorf("node shell is allowed for an admin role only"))
+	}
+
+	client, err := config.Cluster.Client(cluster)
+	if err != nil {
+		return nil, err
+	}
+	api, err := client.NewKubernetesClient()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := api.CoreV1().Nodes().Get(context.TODO(), node, metaV1.GetOptions{}); err != nil {
+		return nil, err
+	}
+
+	expiredAt := time.Now().Add(nodeShellGrantSeconds * time.Second)
+	b, err := json.Marshal(nodeShellClaims{Cluster: cluster, Node: node, User: user, ExpiredAt: expiredAt.Unix()})
+	if err != nil {
+		return nil, err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(b)
+	mac := hmac.New(sha256.New, []byte(config.Value.NodeShellKey))
+	mac.Write([]byte(payload))
+
+	return &NodeShellGrant{
+		Grant:     payload + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)),
+		ExpiredAt: metaV1.NewTime(expiredAt),
+	}, nil
+
+}
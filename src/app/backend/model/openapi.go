@@ -0,0 +1,499 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kore3lab/dashboard/pkg/config"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// validation error types
+const (
+	VALIDATION_UNKNOWN_FIELD  = "UnknownField"
+	VALIDATION_TYPE_ERROR     = "TypeError"
+	VALIDATION_REQUIRED_FIELD = "RequiredField"
+	VALIDATION_ENUM_ERROR     = "EnumError"
+	VALIDATION_INVALID        = "Invalid"
+)
+
+// openapi schema cache expiration
+const openapiCacheTTL = 10 * time.Minute
+
+type openapiDocument map[string]interface{}
+
+// openapi v3 schemas of a cluster
+type openapiCache struct {
+	paths     map[string]string          // "apis/apps/v1" : server relative url
+	documents map[string]openapiDocument // "apis/apps/v1" : document
+	expired   time.Time
+	mu        sync.Mutex
+}
+
+var openapiCaches = struct {
+	items map[string]*openapiCache
+	mu    sync.Mutex
+}{items: make(map[string]*openapiCache)}
+
+type ValidationError struct {
+	Path    string `json:"path"`
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+type ValidationResult struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Name       string            `json:"name"`
+	Namespace  string            `json:"namespace"`
+	Valid      bool              `json:"valid"`
+	Errors     []ValidationError `json:"errors"`
+}
+
+type FieldExplanation struct {
+	APIVersion  string             `json:"apiVersion"`
+	Kind        string             `json:"kind"`
+	Field       string             `json:"field"`
+	Type        string             `json:"type"`
+	Description string             `json:"description"`
+	Required    []string           `json:"required"`
+	Fields      []FieldExplanation `json:"fields,omitempty"`
+}
+
+// get a openapi cache of a cluster (reload if expired)
+func getOpenAPICache(cluster string) (*openapiCache, error) {
+
+	openapiCaches.mu.Lock()
+	cache := openapiCaches.items[cluster]
+	if cache == nil {
+		cache = &openapiCache{}
+		openapiCaches.items[cluster] = cache
+	}
+	openapiCaches.mu.Unlock()
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if cache.paths == nil || time.Now().After(cache.expired) {
+		data, err := getOpenAPIRaw(cluster, "/openapi/v3")
+		if err != nil {
+			return nil, fmt.Errorf("unable to get openapi v3 paths (cause=%s)", err.Error())
+		}
+		discovery := struct {
+			Paths map[string]struct {
+				ServerRelativeURL string `json:"serverRelativeURL"`
+			} `json:"paths"`
+		}{}
+		if err := json.Unmarshal(data, &discovery); err != nil {
+			return nil, err
+		}
+		cache.paths = make(map[string]string)
+		for k, v := range discovery.Paths {
+			cache.paths[k] = v.ServerRelativeURL
+		}
+		cache.documents = make(map[string]openapiDocument)
+		cache.expired = time.Now().Add(openapiCacheTTL)
+	}
+
+	return cache, nil
+
+}
+
+func getOpenAPIRaw(cluster string, uri string) ([]byte, error) {
+
+	client, err := config.Cluster.Client(cluster)
+	if err != nil {
+		return nil, err
+	}
+	discoveryClient, err := client.NewDiscoveryClient()
+	if err != nil {
+		return nil, err
+	}
+	return discoveryClient.RESTClient().Get().RequestURI(uri).SetHeader("Accept", "application/json").Do(context.TODO()).Raw()
+
+}
+
+// openapi v3 paths (group versions)
+func GetOpenAPIPaths(cluster string) ([]string, error) {
+
+	cache, err := getOpenAPICache(cluster)
+	if err != nil {
+		return nil, err
+	}
+	cache.mu.Lock()
+	paths := []string{}
+	for k := range cache.paths {
+		paths = append(paths, k)
+	}
+	cache.mu.Unlock()
+	sort.Strings(paths)
+	return paths, nil
+
+}
+
+// openapi v3 schema of a group version (eg. "v1", "apps/v1")
+func GetOpenAPISchema(cluster string, apiVersion string) (map[string]interface{}, error) {
+	return getOpenAPIDocument(cluster, apiVersion)
+}
+
+func getOpenAPIDocument(cluster string, apiVersion string) (openapiDocument, error) {
+
+	cache, err := getOpenAPICache(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	path := toOpenAPIPath(apiVersion)
+	if doc, exist := cache.documents[path]; exist {
+		return doc, nil
+	}
+	url, exist := cache.paths[path]
+	if !exist {
+		return nil, openapiNotFound("can't find a openapi schema '%s'", apiVersion)
+	}
+	data, err := getOpenAPIRaw(cluster, url)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get a openapi schema '%s' (cause=%s)", apiVersion, err.Error())
+	}
+	doc := openapiDocument{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	cache.documents[path] = doc
+
+	return doc, nil
+
+}
+
+// explain a field of a kind (like "kubectl explain")
+func ExplainField(cluster string, apiVersion string, kind string, field string) (*FieldExplanation, error) {
+
+	doc, err := getOpenAPIDocument(cluster, apiVersion)
+	if err != nil {
+		return nil, err
+	}
+	s := doc.findKind(apiVersion, kind)
+	if s == nil {
+		return nil, openapiNotFound("can't find a schema of '%s' in '%s'", kind, apiVersion)
+	}
+
+	if field != "" {
+		for _, f := range strings.Split(field, ".") {
+			s = doc.resolve(s)
+			if t, _ := s["type"].(string); t == "array" {
+				s, _ = s["items"].(map[string]interface{})
+				s = doc.resolve(s)
+			}
+			props, _ := s["properties"].(map[string]interface{})
+			if props == nil || props[f] == nil {
+				return nil, openapiNotFound("field '%s' does not exist in '%s'", f, field)
+			}
+			s, _ = props[f].(map[string]interface{})
+		}
+	}
+
+	explanation := doc.explain(s, true)
+	explanation.APIVersion = apiVersion
+	explanation.Kind = kind
+	explanation.Field = field
+
+	return &explanation, nil
+
+}
+
+// validate manifests (yaml or json, multi-documents)
+func ValidateManifest(cluster string, payload io.Reader) ([]ValidationResult, error) {
+
+	results := []ValidationResult{}
+	d := yaml.NewYAMLOrJSONDecoder(payload, 4096)
+	for {
+		data := &unstructured.Unstructured{}
+		if err := d.Decode(&data.Object); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, errors.NewBadRequest(err.Error())
+		}
+		if len(data.Object) == 0 {
+			continue
+		}
+
+		result := ValidationResult{
+			APIVersion: data.GetAPIVersion(),
+			Kind:       data.GetKind(),
+			Name:       data.GetName(),
+			Namespace:  data.GetNamespace(),
+			Errors:     []ValidationError{},
+		}
+		if result.APIVersion == "" || result.Kind == "" {
+			result.Errors = append(result.Errors, ValidationError{Type: VALIDATION_INVALID, Message: "apiVersion and kind are required"})
+		} else if doc, err := getOpenAPIDocument(cluster, result.APIVersion); errors.IsNotFound(err) {
+			result.Errors = append(result.Errors, ValidationError{Path: "apiVersion", Type: VALIDATION_INVALID, Message: err.Error()})
+		} else if err != nil {
+			// cluster or transport failures are not validation errors
+			return nil, err
+		} else if s := doc.findKind(result.APIVersion, result.Kind); s == nil {
+			result.Errors = append(result.Errors, ValidationError{Path: "kind", Type: VALIDATION_INVALID, Message: fmt.Sprintf("can't find a kind '%s' in '%s'", result.Kind, result.APIVersion)})
+		} else {
+			result.Errors = doc.validate("", data.Object, s, result.Errors)
+		}
+		result.Valid = (len(result.Errors) == 0)
+		results = append(results, result)
+	}
+
+	return results, nil
+
+}
+
+// unknown apiVersion, kind or field
+func openapiNotFound(format string, a ...interface{}) error {
+	return &errors.StatusError{ErrStatus: metaV1.Status{Status: metaV1.StatusFailure, Code: http.StatusNotFound, Reason: metaV1.StatusReasonNotFound, Message: fmt.Sprintf(format, a...)}}
+}
+
+// "v1" -> "api/v1", "apps/v1" -> "apis/apps/v1"
+func toOpenAPIPath(apiVersion string) string {
+	if strings.Contains(apiVersion, "/") {
+		return "apis/" + apiVersion
+	}
+	return "api/" + apiVersion
+}
+
+// find a schema by "x-kubernetes-group-version-kind"
+func (doc openapiDocument) findKind(apiVersion string, kind string) map[string]interface{} {
+
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return nil
+	}
+	schemas, _, _ := unstructured.NestedMap(doc, "components", "schemas")
+	for _, v := range schemas {
+		s, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		gvks, _ := s["x-kubernetes-group-version-kind"].([]interface{})
+		for _, e := range gvks {
+			if m, ok := e.(map[string]interface{}); ok {
+				if m["group"] == gv.Group && m["version"] == gv.Version && m["kind"] == kind {
+					return s
+				}
+			}
+		}
+	}
+	return nil
+
+}
+
+// resolve "$ref" and single "allOf"
+func (doc openapiDocument) resolve(s map[string]interface{}) map[string]interface{} {
+
+	for i := 0; s != nil && i < 10; i++ {
+		if ref, ok := s["$ref"].(string); ok {
+			name := strings.TrimPrefix(ref, "#/components/schemas/")
+			resolved, _, _ := unstructured.NestedMap(doc, "components", "schemas", name)
+			s = resolved
+		} else if allOf, ok := s["allOf"].([]interface{}); ok && len(allOf) == 1 {
+			s, _ = allOf[0].(map[string]interface{})
+		} else {
+			break
+		}
+	}
+	return s
+
+}
+
+func (doc openapiDocument) explain(s map[string]interface{}, children bool) FieldExplanation {
+
+	explanation := FieldExplanation{Required: []string{}}
+	if d, ok := s["description"].(string); ok {
+		explanation.Description = d
+	}
+	s = doc.resolve(s)
+	if explanation.Description == "" {
+		explanation.Description, _ = s["description"].(string)
+	}
+	explanation.Type = schemaType(s)
+	if t, _ := s["type"].(string); t == "array" {
+		if items, ok := s["items"].(map[string]interface{}); ok {
+			s = doc.resolve(items)
+			explanation.Type = fmt.Sprintf("[]%s", schemaType(s))
+		}
+	}
+	if required, ok := s["required"].([]interface{}); ok {
+		for _, r := range required {
+			explanation.Required = append(explanation.Required, fmt.Sprint(r))
+		}
+	}
+	if props, ok := s["properties"].(map[string]interface{}); ok && children {
+		names := []string{}
+		for k := range props {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		for _, k := range names {
+			p, _ := props[k].(map[string]interface{})
+			f := doc.explain(p, false)
+			f.Field = k
+			explanation.Fields = append(explanation.Fields, f)
+		}
+	}
+	return explanation
+
+}
+
+func schemaType(s map[string]interface{}) string {
+	if s == nil {
+		return ""
+	}
+	if b, _ := s["x-kubernetes-int-or-string"].(bool); b {
+		return "IntOrString"
+	}
+	if t, ok := s["type"].(string); ok {
+		if t == "object" && s["properties"] != nil {
+			return "Object"
+		}
+		return t
+	}
+	if s["properties"] != nil {
+		return "Object"
+	}
+	return ""
+}
+
+// validate a value against a schema
+func (doc openapiDocument) validate(path string, value interface{}, s map[string]interface{}, errs []ValidationError) []ValidationError {
+
+	s = doc.resolve(s)
+	if s == nil || value == nil {
+		return errs
+	}
+	if b, _ := s["x-kubernetes-int-or-string"].(bool); b {
+		switch value.(type) {
+		case string, int64, float64:
+		default:
+			errs = append(errs, ValidationError{Path: path, Type: VALIDATION_TYPE_ERROR, Message: fmt.Sprintf("expected integer or string, got %s", valueType(value))})
+		}
+		return errs
+	}
+
+	// enum
+	if enum, ok := s["enum"].([]interface{}); ok && len(enum) > 0 {
+		found := false
+		for _, e := range enum {
+			if fmt.Sprint(e) == fmt.Sprint(value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errs = append(errs, ValidationError{Path: path, Type: VALIDATION_ENUM_ERROR, Message: fmt.Sprintf("unsupported value %v, supported values: %v", value, enum)})
+		}
+	}
+
+	t, _ := s["type"].(string)
+	if t == "" && s["properties"] != nil {
+		t = "object"
+	}
+
+	switch t {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return append(errs, ValidationError{Path: path, Type: VALIDATION_TYPE_ERROR, Message: fmt.Sprintf("expected object, got %s", valueType(value))})
+		}
+		if required, ok := s["required"].([]interface{}); ok {
+			for _, r := range required {
+				if _, exist := obj[fmt.Sprint(r)]; !exist {
+					errs = append(errs, ValidationError{Path: joinPath(path, fmt.Sprint(r)), Type: VALIDATION_REQUIRED_FIELD, Message: "required field is missing"})
+				}
+			}
+		}
+		preserve, _ := s["x-kubernetes-preserve-unknown-fields"].(bool)
+		props, _ := s["properties"].(map[string]interface{})
+		additional, _ := s["additionalProperties"].(map[string]interface{})
+		keys := []string{}
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			p := joinPath(path, k)
+			if ps, exist := props[k].(map[string]interface{}); exist {
+				errs = doc.validate(p, obj[k], ps, errs)
+			} else if additional != nil {
+				errs = doc.validate(p, obj[k], additional, errs)
+			} else if props != nil && !preserve && s["additionalProperties"] != true {
+				errs = append(errs, ValidationError{Path: p, Type: VALIDATION_UNKNOWN_FIELD, Message: fmt.Sprintf("unknown field \"%s\"", k)})
+			}
+		}
+	case "array":
+		arr, ok := value.([]interface{})
+		if !ok {
+			return append(errs, ValidationError{Path: path, Type: VALIDATION_TYPE_ERROR, Message: fmt.Sprintf("expected array, got %s", valueType(value))})
+		}
+		if items, ok := s["items"].(map[string]interface{}); ok {
+			for i, e := range arr {
+				errs = doc.validate(fmt.Sprintf("%s[%d]", path, i), e, items, errs)
+			}
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			errs = append(errs, ValidationError{Path: path, Type: VALIDATION_TYPE_ERROR, Message: fmt.Sprintf("expected string, got %s", valueType(value))})
+		}
+	case "integer":
+		switch v := value.(type) {
+		case int64:
+		case float64:
+			if v != float64(int64(v)) {
+				errs = append(errs, ValidationError{Path: path, Type: VALIDATION_TYPE_ERROR, Message: fmt.Sprintf("expected integer, got %v", v)})
+			}
+		default:
+			errs = append(errs, ValidationError{Path: path, Type: VALIDATION_TYPE_ERROR, Message: fmt.Sprintf("expected integer, got %s", valueType(value))})
+		}
+	case "number":
+		switch value.(type) {
+		case int64, float64:
+		default:
+			errs = append(errs, ValidationError{Path: path, Type: VALIDATION_TYPE_ERROR, Message: fmt.Sprintf("expected number, got %s", valueType(value))})
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			errs = append(errs, ValidationError{Path: path, Type: VALIDATION_TYPE_ERROR, Message: fmt.Sprintf("expected boolean, got %s", valueType(value))})
+		}
+	}
+
+	return errs
+
+}
+
+func valueType(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case int64:
+		return "integer"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return fmt.Sprintf("%T", value)
+}
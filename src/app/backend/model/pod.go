@@ -61,19 +61,17 @@ func GetPodsMatchLabels(k8sClient *kubernetes.Clientset, namespace string, selec
 
 }
 
-// deployment's available-ready count in a cluster
-func GetPodsReady(apiClient *kubernetes.Clientset, options metaV1.ListOptions) (available int, ready int, err error) {
+// pod's available-ready count in a cluster (namespace = "" : all namespaces), ready : running & ready or completed
+func GetPodsReady(apiClient *kubernetes.Clientset, namespace string, options metaV1.ListOptions) (available int, ready int, err error) {
 
-	list, err := apiClient.CoreV1().Pods("").List(context.TODO(), options)
+	list, err := apiClient.CoreV1().Pods(namespace).List(context.TODO(), options)
 	if err != nil {
 		return available, ready, err
 	}
 	available = len(list.Items)
 	for _, m := range list.Items {
-		if m.Spec.NodeName != "" {
-			if m.Status.Phase == coreV1.PodRunning {
-				ready += 1
-			}
+		if severity, _, _ := lang.GetPodHealth(m); severity == lang.HEALTH_OK {
+			ready += 1
 		}
 	}
 
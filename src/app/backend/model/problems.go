@@ -0,0 +1,174 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/kore3lab/dashboard/pkg/config"
+	"github.com/kore3lab/dashboard/pkg/lang"
+	appsV1 "k8s.io/api/apps/v1"
+	batchV1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+)
+
+// remediation hints (by reason)
+var problemHints = map[string]string{
+	"CrashLoopBackOff":           "Check logs of the previous container (kubectl logs --previous) and the container command, probes and configuration.",
+	"ImagePullBackOff":           "Check the image name and tag, registry accessibility and imagePullSecrets.",
+	"ErrImagePull":               "Check the image name and tag, registry accessibility and imagePullSecrets.",
+	"InvalidImageName":           "Fix the image reference of the container.",
+	"CreateContainerConfigError": "Check referenced configmaps, secrets and keys exist.",
+	"CreateContainerError":       "Check the container command, volume mounts and security context.",
+	"RunContainerError":          "Check the container command, volume mounts and security context.",
+	"OOMKilled":                  "Increase the memory limit or reduce memory usage of the container.",
+	"Unschedulable":              "Check node resources, node selectors, affinities, taints and tolerations, or add nodes.",
+	"Evicted":                    "Check node resource pressure (memory, disk) and set requests to avoid eviction.",
+	"NotReady":                   "Check readiness probes and container logs.",
+	"BackoffLimitExceeded":       "Check logs of failed pods, fix the job and re-run it.",
+	"DeadlineExceeded":           "Increase activeDeadlineSeconds or make the job complete faster.",
+	"ProgressDeadlineExceeded":   "Check pods of the new replicaset (image, resources, probes) or roll back the deployment.",
+	"UnreadyReplicas":            "Check pods of the statefulset, statefulset pods are created in order and wait for a previous pod ready.",
+	"NodeNotReady":               "Check kubelet, container runtime and network of the node.",
+}
+
+type Problem struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Severity  string `json:"severity"` // Warning, Error
+	Reason    string `json:"reason"`
+	Message   string `json:"message"`
+	Hint      string `json:"hint"` // remediation hint
+}
+
+// scan pods, jobs, deployments, statefulsets (and nodes on cluster scope) and classify problems
+func GetProblems(cluster string, namespace string) ([]Problem, error) {
+
+	clientSet, err := config.Cluster.Client(cluster)
+	if err != nil {
+		return nil, err
+	}
+	apiClient, err := clientSet.NewKubernetesClient()
+	if err != nil {
+		return nil, err
+	}
+
+	problems := []Problem{}
+	add := func(kind string, meta metaV1.ObjectMeta, severity string, reason string, message string) {
+		problems = append(problems, Problem{Kind: kind, Namespace: meta.Namespace, Name: meta.Name, Severity: severity, Reason: reason, Message: message, Hint: problemHints[reason]})
+	}
+
+	// pods
+	pods, err := apiClient.CoreV1().Pods(namespace).List(context.TODO(), metaV1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var scheduling map[string]string
+	for _, pod := range pods.Items {
+		severity, reason, message := lang.GetPodHealth(pod)
+		if severity == lang.HEALTH_OK {
+			continue
+		}
+		if pod.Status.Phase == coreV1.PodPending && pod.Spec.NodeName == "" {
+			// unschedulable events
+			if scheduling == nil {
+				if scheduling, err = getFailedSchedulingEvents(apiClient, namespace); err != nil {
+					return nil, err
+				}
+			}
+			if msg, ok := scheduling[fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)]; ok {
+				severity, reason, message = lang.HEALTH_ERROR, coreV1.PodReasonUnschedulable, msg
+			}
+		}
+		add(ELEMENT_KIND_POD, pod.ObjectMeta, severity, reason, message)
+	}
+
+	// jobs
+	jobs, err := apiClient.BatchV1().Jobs(namespace).List(context.TODO(), metaV1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, job := range jobs.Items {
+		for _, c := range job.Status.Conditions {
+			if c.Type == batchV1.JobFailed && c.Status == coreV1.ConditionTrue {
+				add("Job", job.ObjectMeta, lang.HEALTH_ERROR, lang.NVL(c.Reason, "Failed"), c.Message)
+			}
+		}
+	}
+
+	// deployments
+	deployments, err := apiClient.AppsV1().Deployments(namespace).List(context.TODO(), metaV1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, deploy := range deployments.Items {
+		for _, c := range deploy.Status.Conditions {
+			if c.Type == appsV1.DeploymentProgressing && c.Status == coreV1.ConditionFalse && c.Reason == "ProgressDeadlineExceeded" {
+				add("Deployment", deploy.ObjectMeta, lang.HEALTH_ERROR, c.Reason, c.Message)
+			}
+		}
+	}
+
+	// statefulsets
+	statefulsets, err := apiClient.AppsV1().StatefulSets(namespace).List(context.TODO(), metaV1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, sts := range statefulsets.Items {
+		replicas := desiredReplicas(sts.Spec.Replicas)
+		if sts.Status.ReadyReplicas < replicas {
+			add("StatefulSet", sts.ObjectMeta, lang.HEALTH_WARNING, "UnreadyReplicas", fmt.Sprintf("%d/%d replicas are ready", sts.Status.ReadyReplicas, replicas))
+		}
+	}
+
+	// nodes (cluster scope)
+	if namespace == "" {
+		nodes, err := apiClient.CoreV1().Nodes().List(context.TODO(), metaV1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, node := range nodes.Items {
+			for _, c := range node.Status.Conditions {
+				if c.Type == coreV1.NodeReady && c.Status != coreV1.ConditionTrue {
+					add(ELEMENT_KIND_NODE, node.ObjectMeta, lang.HEALTH_ERROR, "NodeNotReady", lang.NVL(c.Message, c.Reason))
+				}
+			}
+		}
+	}
+
+	// errors first
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Severity == lang.HEALTH_ERROR && problems[j].Severity != lang.HEALTH_ERROR
+	})
+
+	return problems, nil
+
+}
+
+// latest "FailedScheduling" event messages by pods ("namespace/name")
+func getFailedSchedulingEvents(apiClient *kubernetes.Clientset, namespace string) (map[string]string, error) {
+
+	events, err := apiClient.CoreV1().Events(namespace).List(context.TODO(), metaV1.ListOptions{
+		FieldSelector: fields.AndSelectors(fields.OneTermEqualSelector("reason", "FailedScheduling"), fields.OneTermEqualSelector("involvedObject.kind", ELEMENT_KIND_POD)).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	messages := map[string]string{}
+	latest := map[string]metaV1.Time{}
+	for _, ev := range events.Items {
+		key := fmt.Sprintf("%s/%s", ev.InvolvedObject.Namespace, ev.InvolvedObject.Name)
+		ts := eventTimestamp(ev)
+		if t, ok := latest[key]; !ok || t.Before(&ts) {
+			latest[key] = ts
+			messages[key] = ev.Message
+		}
+	}
+	return messages, nil
+
+}
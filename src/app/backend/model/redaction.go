@@ -0,0 +1,332 @@
+package model
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/kore3lab/dashboard/pkg/config"
+	log "github.com/sirupsen/logrus"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const (
+	REDACTED_VALUE      = "********"
+	REDACTED_ANNOTATION = "kore3lab.io/redacted"
+	maxRevealRecords    = 1000
+)
+
+// base64 encoded REDACTED_VALUE (secret data)
+var redactedSecretValue = base64.StdEncoding.EncodeToString([]byte(REDACTED_VALUE))
+
+// audit record of a revealed key
+type RevealRecord struct {
+	User      string    `json:"user"`
+	Address   string    `json:"address"`
+	Cluster   string    `json:"cluster"`
+	Namespace string    `json:"namespace"`
+	Resource  string    `json:"resource"`
+	Name      string    `json:"name"`
+	Key       string    `json:"key"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+var revealRecords = struct {
+	items []RevealRecord
+	mu    sync.RWMutex
+}{items: []RevealRecord{}}
+
+// redact secret data & sensitive configmap keys
+func RedactObject(obj *unstructured.Unstructured) {
+
+	if obj == nil || obj.GetAPIVersion() != "v1" {
+		return
+	}
+
+	redacted := false
+	if obj.GetKind() == "Secret" && config.Value.RedactSecrets {
+		if data, ok := obj.Object["data"].(map[string]interface{}); ok {
+			for k := range data {
+				data[k] = redactedSecretValue
+				redacted = true
+			}
+		}
+		if data, ok := obj.Object["stringData"].(map[string]interface{}); ok {
+			for k := range data {
+				data[k] = REDACTED_VALUE
+				redacted = true
+			}
+		}
+	} else if obj.GetKind() == "ConfigMap" && len(config.Value.RedactConfigMapKeys) > 0 {
+		for _, field := range []string{"data", "binaryData"} {
+			if data, ok := obj.Object[field].(map[string]interface{}); ok {
+				for k := range data {
+					if isSensitiveConfigMapKey(k) {
+						if field == "binaryData" {
+							data[k] = redactedSecretValue
+						} else {
+							data[k] = REDACTED_VALUE
+						}
+						redacted = true
+					}
+				}
+			}
+		}
+	}
+
+	if redacted {
+		annotations := obj.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[REDACTED_ANNOTATION] = "true"
+		obj.SetAnnotations(annotations)
+	}
+
+}
+
+// redact a list
+func RedactList(list *unstructured.UnstructuredList) {
+	if list == nil {
+		return
+	}
+	for i := range list.Items {
+		RedactObject(&list.Items[i])
+	}
+}
+
+func isSensitiveConfigMapKey(key string) bool {
+	for _, pattern := range config.Value.RedactConfigMapKeys {
+		if matched, _ := path.Match(pattern, key); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// restore redacted values from existing object before apply, with or without the redacted annotation (returns a new payload)
+func RestoreRedacted(cluster string, payload io.Reader) (io.Reader, error) {
+
+	out := &bytes.Buffer{}
+	d := yaml.NewYAMLOrJSONDecoder(payload, 4096)
+	for {
+		data := &unstructured.Unstructured{}
+		if err := d.Decode(&data.Object); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		if len(data.Object) == 0 {
+			continue
+		}
+
+		annotations := data.GetAnnotations()
+		_, exist := annotations[REDACTED_ANNOTATION]
+		if data.GetAPIVersion() == "v1" && (data.GetKind() == "Secret" || data.GetKind() == "ConfigMap") && (exist || hasRedactedValues(data)) {
+			delete(annotations, REDACTED_ANNOTATION)
+			data.SetAnnotations(annotations)
+			resource := "secrets"
+			if data.GetKind() == "ConfigMap" {
+				resource = "configmaps"
+			}
+			if err := restoreRedactedValues(cluster, resource, data.GetNamespace(), data.GetName(), data); err != nil {
+				return nil, err
+			}
+		}
+
+		b, err := json.Marshal(data.Object)
+		if err != nil {
+			return nil, err
+		}
+		out.Write(b)
+	}
+
+	return out, nil
+
+}
+
+// restore redacted values of a patch from existing object (merge patches only, returns a new payload)
+func RestoreRedactedPatch(cluster string, group string, resource string, namespace string, name string, patchType types.PatchType, payload io.Reader) (io.Reader, error) {
+
+	b, err := ioutil.ReadAll(payload)
+	if err != nil {
+		return nil, err
+	}
+	if group != "" || (resource != "secrets" && resource != "configmaps") || !(bytes.Contains(b, []byte(REDACTED_VALUE)) || bytes.Contains(b, []byte(redactedSecretValue)) || bytes.Contains(b, []byte(REDACTED_ANNOTATION))) {
+		return bytes.NewReader(b), nil
+	}
+	if patchType != types.MergePatchType && patchType != types.StrategicMergePatchType {
+		return nil, fmt.Errorf("patch of '%s' contains redacted values, use a merge patch or omit the redacted keys", name)
+	}
+
+	data := &unstructured.Unstructured{}
+	if err := json.Unmarshal(b, &data.Object); err != nil {
+		return nil, err
+	}
+	if annotations, ok, _ := unstructured.NestedMap(data.Object, "metadata", "annotations"); ok {
+		if _, exist := annotations[REDACTED_ANNOTATION]; exist {
+			unstructured.RemoveNestedField(data.Object, "metadata", "annotations", REDACTED_ANNOTATION)
+		}
+	}
+	if err := restoreRedactedValues(cluster, resource, namespace, name, data); err != nil {
+		return nil, err
+	}
+
+	if b, err = json.Marshal(data.Object); err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(b), nil
+
+}
+
+// placeholders in data, stringData or binaryData
+func hasRedactedValues(obj *unstructured.Unstructured) bool {
+	for _, field := range []string{"data", "stringData", "binaryData"} {
+		data, _ := obj.Object[field].(map[string]interface{})
+		for _, v := range data {
+			if v == REDACTED_VALUE || v == redactedSecretValue {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func restoreRedactedValues(cluster string, resource string, namespace string, name string, obj *unstructured.Unstructured) error {
+
+	fields := []string{"data", "stringData"}
+	if resource == "configmaps" {
+		fields = []string{"data", "binaryData"}
+	}
+
+	client, err := config.Cluster.Client(cluster)
+	if err != nil {
+		return err
+	}
+	api, err := client.NewDynamicClientSchema("", "v1", resource)
+	if err != nil {
+		return err
+	}
+	api.SetNamespace(namespace)
+	existing, err := api.GET(name, metaV1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to restore redacted values of '%s' (cause=%s)", name, err.Error())
+	}
+
+	for _, field := range fields {
+		data, ok := obj.Object[field].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		current, _ := existing.Object[field].(map[string]interface{})
+		if field == "stringData" {
+			current, _ = existing.Object["data"].(map[string]interface{})
+		}
+		for k, v := range data {
+			if v != REDACTED_VALUE && v != redactedSecretValue {
+				continue
+			}
+			if current == nil || current[k] == nil {
+				return fmt.Errorf("unable to restore redacted key '%s' of '%s'", k, name)
+			}
+			if field == "stringData" {
+				// move to "data" (existing values are base64 encoded)
+				delete(data, k)
+				if obj.Object["data"] == nil {
+					obj.Object["data"] = map[string]interface{}{}
+				}
+				obj.Object["data"].(map[string]interface{})[k] = current[k]
+			} else {
+				data[k] = current[k]
+			}
+		}
+	}
+	return nil
+
+}
+
+// reveal a key of a secret or a configmap (audited)
+func RevealKey(record RevealRecord) (string, error) {
+
+	client, err := config.Cluster.Client(record.Cluster)
+	if err != nil {
+		return "", err
+	}
+	api, err := client.NewDynamicClientSchema("", "v1", record.Resource)
+	if err != nil {
+		return "", err
+	}
+	api.SetNamespace(record.Namespace)
+	obj, err := api.GET(record.Name, metaV1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	value := ""
+	if record.Resource == "secrets" {
+		v, exist, _ := unstructured.NestedString(obj.Object, "data", record.Key)
+		if !exist {
+			return "", fmt.Errorf("key '%s' does not exist in '%s'", record.Key, record.Name)
+		}
+		b, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return "", err
+		}
+		value = string(b)
+	} else {
+		v, exist, _ := unstructured.NestedString(obj.Object, "data", record.Key)
+		if !exist {
+			if v, exist, _ = unstructured.NestedString(obj.Object, "binaryData", record.Key); !exist {
+				return "", fmt.Errorf("key '%s' does not exist in '%s'", record.Key, record.Name)
+			}
+		}
+		value = v
+	}
+
+	// audit
+	record.Timestamp = time.Now()
+	log.WithFields(log.Fields{
+		"audit":     "reveal",
+		"user":      record.User,
+		"address":   record.Address,
+		"cluster":   record.Cluster,
+		"namespace": record.Namespace,
+		"resource":  record.Resource,
+		"name":      record.Name,
+		"key":       record.Key,
+	}).Info("revealed a redacted key")
+
+	revealRecords.mu.Lock()
+	revealRecords.items = append(revealRecords.items, record)
+	if len(revealRecords.items) > maxRevealRecords {
+		revealRecords.items = revealRecords.items[len(revealRecords.items)-maxRevealRecords:]
+	}
+	revealRecords.mu.Unlock()
+
+	return value, nil
+
+}
+
+// audit records of revealed keys (latest first)
+func GetRevealRecords() []RevealRecord {
+
+	revealRecords.mu.RLock()
+	defer revealRecords.mu.RUnlock()
+
+	records := make([]RevealRecord, 0, len(revealRecords.items))
+	for i := len(revealRecords.items) - 1; i >= 0; i-- {
+		records = append(records, revealRecords.items[i])
+	}
+	return records
+
+}
@@ -44,16 +44,16 @@ func GetReplicaSetMatchLabels(k8sClient *kubernetes.Clientset, namespace string,
 
 }
 
-// replicaset's available-ready count in a cluster
-func GetReplicaSetsReady(apiClient *kubernetes.Clientset, options metaV1.ListOptions) (available int, ready int, err error) {
+// replicaset's available-ready count in a cluster (namespace = "" : all namespaces)
+func GetReplicaSetsReady(apiClient *kubernetes.Clientset, namespace string, options metaV1.ListOptions) (available int, ready int, err error) {
 
-	list, err := apiClient.AppsV1().ReplicaSets("").List(context.TODO(), options)
+	list, err := apiClient.AppsV1().ReplicaSets(namespace).List(context.TODO(), options)
 	if err != nil {
 		return available, ready, err
 	}
 	available = len(list.Items)
 	for _, m := range list.Items {
-		if m.Status.Replicas == m.Status.ReadyReplicas {
+		if m.Status.ReadyReplicas >= desiredReplicas(m.Spec.Replicas) {
 			ready += 1
 		}
 	}
@@ -0,0 +1,234 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/kore3lab/dashboard/pkg/client"
+	"github.com/kore3lab/dashboard/pkg/config"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	RIGHTSIZING_OK                = "OK"
+	RIGHTSIZING_OVER_PROVISIONED  = "OverProvisioned"
+	RIGHTSIZING_UNDER_PROVISIONED = "UnderProvisioned"
+	RIGHTSIZING_UNKNOWN           = "Unknown" // no metrics history
+
+	defaultRightSizingPercentile = 95
+	defaultRightSizingHeadroom   = 0.15
+	rightSizingOverRatio         = 1.5      // over-provisioned : request > recommended x 1.5
+	rightSizingLimitRatio        = 0.9      // under-provisioned : max usage > limit x 0.9
+	minCPURequest                = 10       // millicores
+	minMemoryRequest             = 16 << 20 // bytes (16Mi)
+)
+
+type ContainerRightSizing struct {
+	Name        string       `json:"name"`
+	Samples     int          `json:"samples"` // number of metrics history points
+	Current     MetricsUsage `json:"current"` // current requests, limits and percentile usage
+	Max         MetricUnit   `json:"max"`     // max usage
+	Recommended struct {
+		Requests MetricUnit `json:"requests"`
+		Limits   MetricUnit `json:"limits"` // 0 : no limit
+	} `json:"recommended"`
+	CPU    string `json:"cpu"`    // OK, OverProvisioned, UnderProvisioned, Unknown
+	Memory string `json:"memory"` // OK, OverProvisioned, UnderProvisioned, Unknown
+}
+
+type RightSizing struct {
+	Namespace  string                 `json:"namespace"`
+	Resource   string                 `json:"resource"`
+	Name       string                 `json:"name"`
+	Percentile float64                `json:"percentile"`
+	Headroom   float64                `json:"headroom"`
+	Status     string                 `json:"status"`
+	Containers []ContainerRightSizing `json:"containers"`
+	PatchType  types.PatchType        `json:"patchType"`
+	Patch      map[string]interface{} `json:"patch"` // nil : pods, jobs (resources are immutable) or no recommendations
+}
+
+// percentile-based requests/limits recommendations of a workload (pods, deployments, statefulsets, daemonsets, replicasets, jobs, cronjobs)
+// percentile : usage percentile (default: 95), headroom : ratio added to usage (default: 0.15)
+func GetRightSizing(cluster string, namespace string, resourceName string, name string, percentile float64, headroom float64) (*RightSizing, error) {
+
+	if percentile == 0 {
+		percentile = defaultRightSizingPercentile
+	} else if percentile < 0 || percentile > 100 {
+		return nil, fmt.Errorf("percentile must be between 0 and 100")
+	}
+	if headroom < 0 {
+		return nil, fmt.Errorf("headroom must be greater than or equal to 0")
+	} else if headroom == 0 {
+		headroom = defaultRightSizingHeadroom
+	}
+
+	clientSet, err := config.Cluster.Client(cluster)
+	if err != nil {
+		return nil, err
+	}
+	apiClient, err := clientSet.NewKubernetesClient()
+	if err != nil {
+		return nil, err
+	}
+
+	pods, podSpec, err := getWorkloadPods(apiClient, namespace, resourceName, name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RightSizing{
+		Namespace:  namespace,
+		Resource:   resourceName,
+		Name:       name,
+		Percentile: percentile,
+		Headroom:   headroom,
+		Status:     RIGHTSIZING_UNKNOWN,
+		Containers: []ContainerRightSizing{},
+		PatchType:  types.StrategicMergePatchType,
+	}
+	if podSpec == nil || len(pods) == 0 {
+		return result, nil
+	}
+
+	names := []string{}
+	for _, pd := range pods {
+		names = append(names, pd.ObjectMeta.Name)
+	}
+
+	metricsClient := clientSet.NewCumulativeMetricsClient()
+	patches := []interface{}{}
+	for _, c := range podSpec.Containers {
+		r := ContainerRightSizing{Name: c.Name, CPU: RIGHTSIZING_UNKNOWN, Memory: RIGHTSIZING_UNKNOWN}
+		r.Current.Requests = MetricUnit{CPU: c.Resources.Requests.Cpu().MilliValue(), Memory: c.Resources.Requests.Memory().Value()}
+		r.Current.Limits = MetricUnit{CPU: c.Resources.Limits.Cpu().MilliValue(), Memory: c.Resources.Limits.Memory().Value()}
+
+		// history (max of pods at each point)
+		metrics, err := metricsClient.Get(client.CumulativeMetricsResourceSelector{
+			Namespace: namespace,
+			Pods:      names,
+			Container: c.Name,
+			Function:  "MAX",
+		})
+		if err != nil {
+			return nil, err
+		}
+		r.Samples = len(metrics)
+		if r.Samples > 0 {
+			r.recommend(metrics, percentile, headroom)
+			patches = append(patches, r.patch())
+		}
+		result.Containers = append(result.Containers, r)
+	}
+
+	// workload status (under-provisioned > over-provisioned > ok)
+	for _, r := range result.Containers {
+		for _, s := range []string{r.CPU, r.Memory} {
+			if s == RIGHTSIZING_UNDER_PROVISIONED || (s == RIGHTSIZING_OVER_PROVISIONED && result.Status != RIGHTSIZING_UNDER_PROVISIONED) || (s == RIGHTSIZING_OK && result.Status == RIGHTSIZING_UNKNOWN) {
+				result.Status = s
+			}
+		}
+	}
+
+	// pod (and job template) resources are immutable
+	if len(patches) > 0 {
+		template := map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{"containers": patches},
+			},
+		}
+		switch resourceName {
+		case "pods", "jobs":
+		case "cronjobs":
+			result.Patch = map[string]interface{}{"spec": map[string]interface{}{"jobTemplate": map[string]interface{}{"spec": template}}}
+		default:
+			result.Patch = map[string]interface{}{"spec": template}
+		}
+	}
+
+	return result, nil
+
+}
+
+// calculate recommendations & status of a container
+func (me *ContainerRightSizing) recommend(metrics []client.CumulativeMetricUnit, percentile float64, headroom float64) {
+
+	cpu, memory := []int64{}, []int64{}
+	for _, m := range metrics {
+		cpu = append(cpu, m.CPU)
+		memory = append(memory, m.Memory)
+	}
+	me.Current.Usage = MetricUnit{CPU: nearestRank(cpu, percentile), Memory: nearestRank(memory, percentile)}
+	me.Max = MetricUnit{CPU: cpu[len(cpu)-1], Memory: memory[len(memory)-1]}
+
+	// requests : percentile usage + headroom, limits : max usage + headroom
+	me.Recommended.Requests.CPU = maxInt64(int64(math.Ceil(float64(me.Current.Usage.CPU)*(1+headroom))), minCPURequest)
+	me.Recommended.Requests.Memory = roundUpMi(maxInt64(int64(math.Ceil(float64(me.Current.Usage.Memory)*(1+headroom))), minMemoryRequest))
+	me.Recommended.Limits.Memory = roundUpMi(maxInt64(int64(math.Ceil(float64(me.Max.Memory)*(1+headroom))), me.Recommended.Requests.Memory))
+	if me.Current.Limits.CPU > 0 {
+		// keep cpu unlimited if no limit
+		me.Recommended.Limits.CPU = maxInt64(int64(math.Ceil(float64(me.Max.CPU)*(1+headroom))), me.Recommended.Requests.CPU)
+	}
+
+	me.CPU = rightSizingStatus(me.Current.Requests.CPU, me.Current.Usage.CPU, me.Recommended.Requests.CPU)
+	me.Memory = rightSizingStatus(me.Current.Requests.Memory, me.Current.Usage.Memory, me.Recommended.Requests.Memory)
+	if me.Current.Limits.Memory > 0 && float64(me.Max.Memory) > float64(me.Current.Limits.Memory)*rightSizingLimitRatio {
+		me.Memory = RIGHTSIZING_UNDER_PROVISIONED // close to OOMKilled
+	}
+
+}
+
+// a container patch (strategic-merge, merged by name)
+func (me *ContainerRightSizing) patch() map[string]interface{} {
+
+	resources := map[string]interface{}{
+		"requests": map[string]string{
+			"cpu":    resource.NewMilliQuantity(me.Recommended.Requests.CPU, resource.DecimalSI).String(),
+			"memory": resource.NewQuantity(me.Recommended.Requests.Memory, resource.BinarySI).String(),
+		},
+	}
+	limits := map[string]string{
+		"memory": resource.NewQuantity(me.Recommended.Limits.Memory, resource.BinarySI).String(),
+	}
+	if me.Recommended.Limits.CPU > 0 {
+		limits["cpu"] = resource.NewMilliQuantity(me.Recommended.Limits.CPU, resource.DecimalSI).String()
+	}
+	resources["limits"] = limits
+
+	return map[string]interface{}{"name": me.Name, "resources": resources}
+
+}
+
+// request unset or below usage : under-provisioned, request above recommended x 1.5 : over-provisioned
+func rightSizingStatus(request int64, usage int64, recommended int64) string {
+	if request == 0 || request < usage {
+		return RIGHTSIZING_UNDER_PROVISIONED
+	} else if float64(request) > float64(recommended)*rightSizingOverRatio {
+		return RIGHTSIZING_OVER_PROVISIONED
+	}
+	return RIGHTSIZING_OK
+}
+
+// nearest-rank percentile (sorts values)
+func nearestRank(values []int64, percentile float64) int64 {
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	rank := int(math.Ceil(percentile/100*float64(len(values)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return values[rank]
+}
+
+// round up to Mi
+func roundUpMi(bytes int64) int64 {
+	return int64(math.Ceil(float64(bytes)/float64(1<<20))) << 20
+}
+
+func maxInt64(a int64, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
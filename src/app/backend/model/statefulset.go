@@ -29,16 +29,16 @@ func GetStatefulSetPods(apiClient *kubernetes.Clientset, namespace string, name
 
 }
 
-// statefulset's available-ready count in a cluster
-func GetStatefulSetsReady(apiClient *kubernetes.Clientset, options metaV1.ListOptions) (available int, ready int, err error) {
+// statefulset's available-ready count in a cluster (namespace = "" : all namespaces)
+func GetStatefulSetsReady(apiClient *kubernetes.Clientset, namespace string, options metaV1.ListOptions) (available int, ready int, err error) {
 
-	list, err := apiClient.AppsV1().StatefulSets("").List(context.TODO(), options)
+	list, err := apiClient.AppsV1().StatefulSets(namespace).List(context.TODO(), options)
 	if err != nil {
 		return available, ready, err
 	}
 	available = len(list.Items)
 	for _, m := range list.Items {
-		if m.Status.Replicas == m.Status.ReadyReplicas {
+		if m.Status.ReadyReplicas >= desiredReplicas(m.Spec.Replicas) {
 			ready += 1
 		}
 	}
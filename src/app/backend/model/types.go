@@ -28,13 +28,14 @@ func NewStatus(code int) *Status {
 // hierarchy-graph
 type Hierarchy map[string][]HierarchyNode
 type HierarchyNode struct {
-	UID        string `json:"uid"`
-	Name       string `json:"name"`
-	APIVersion string `json:"apiVersion"`
-	Kind       string `json:"kind"`
-	Namespace  string `json:"namespace"`
-	Line       string `json:"line"`
-	Owner      string `json:"owner"`
+	UID        string            `json:"uid"`
+	Name       string            `json:"name"`
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Namespace  string            `json:"namespace"`
+	Line       string            `json:"line"`
+	Owner      string            `json:"owner"`
+	Attributes map[string]string `json:"attributes,omitempty"` // e.g. capacity, accessModes, phase, orphaned
 }
 
 // topology-graph
@@ -43,12 +44,24 @@ type Topology struct {
 	Links []topologyLink `json:"links"`
 }
 type topologyNode struct {
-	Id        string `json:"id"`
-	Name      string `json:"name"`
-	Kind      string `json:"kind"`
-	Namespace string `json:"namespace"`
-	Group     string `json:"group"`
-	Labels    string `json:"labels"`
+	Id        string           `json:"id"`
+	Name      string           `json:"name"`
+	Kind      string           `json:"kind"`
+	Namespace string           `json:"namespace"`
+	Group     string           `json:"group"`
+	Labels    string           `json:"labels"`
+	Metrics   *TopologyMetrics `json:"metrics,omitempty"` // pods, nodes (metrics overlay)
+}
+type TopologyMetrics struct {
+	Usage       *MetricUnit `json:"usage,omitempty"` // unset : no metrics sample
+	Capacity    MetricUnit  `json:"capacity"`        // requests (pods), allocatable (nodes)
+	Utilization struct {
+		CPU    *float64 `json:"cpu,omitempty"`    // ratio of usage to capacity (unset : no sample or no capacity)
+		Memory *float64 `json:"memory,omitempty"` // ratio of usage to capacity (unset : no sample or no capacity)
+	} `json:"utilization"`
+	Health     string            `json:"health"`               // OK, Warning, Error
+	Restarts   int32             `json:"restarts"`             // pods
+	Conditions map[string]string `json:"conditions,omitempty"` // nodes (type : status)
 }
 type topologyLink struct {
 	Source string `json:"source"`
@@ -64,6 +77,22 @@ const (
 	ELEMENT_KIND_NODE       string = "Node"
 	ELEMENT_KIND_REPLICASET string = "ReplicaSet"
 	ELEMENT_KIND_CONTAINER  string = "Container"
+
+	ELEMENT_KIND_USER                 string = "User"
+	ELEMENT_KIND_GROUP                string = "Group"
+	ELEMENT_KIND_SERVICE_ACCOUNT      string = "ServiceAccount"
+	ELEMENT_KIND_ROLE_BINDING         string = "RoleBinding"
+	ELEMENT_KIND_CLUSTER_ROLE_BINDING string = "ClusterRoleBinding"
+	ELEMENT_KIND_ROLE                 string = "Role"
+	ELEMENT_KIND_CLUSTER_ROLE         string = "ClusterRole"
+
+	ELEMENT_KIND_CONFIGMAP               string = "ConfigMap"
+	ELEMENT_KIND_SECRET                  string = "Secret"
+	ELEMENT_KIND_PERSISTENT_VOLUME_CLAIM string = "PersistentVolumeClaim"
+	ELEMENT_KIND_SERVICE                 string = "Service"
+	ELEMENT_KIND_INGRESS                 string = "Ingress"
+	ELEMENT_KIND_IPBLOCK                 string = "IPBlock"
+	ELEMENT_KIND_ANY                     string = "Any" // any pods (not isolated)
 )
 
 // metrics
@@ -104,13 +104,14 @@ func LocalAuthenticator(accessKey string, refreshKey string, validateFunc Valida
 					return
 				}
 			}
+			c.Set(ContextUsername, GetSessionUsername(accessToken))
 			c.Next()
 		}
 	}
 
 	//login, refresh, logout callback
 	h.LoginHandler = func(params map[string]string) (interface{}, error) {
-		return newJWTToken(accessKey, refreshKey)
+		return newJWTToken(accessKey, refreshKey, loginUsername(params))
 	}
 	h.RefreshHandler = func(params map[string]string) (interface{}, error) {
 		// validating refresh-token
@@ -120,7 +121,7 @@ func LocalAuthenticator(accessKey string, refreshKey string, validateFunc Valida
 			return nil, errors.New("refresh token expired")
 		} else {
 			// new access, refresh token
-			return newJWTToken(refreshKey, refreshKey)
+			return newJWTToken(refreshKey, refreshKey, GetSessionUsername(params["refreshToken"]))
 		}
 	}
 
@@ -128,13 +129,13 @@ func LocalAuthenticator(accessKey string, refreshKey string, validateFunc Valida
 
 }
 
-func newJWTToken(accessSecret string, refreshSecret string) (map[string]string, error) {
+func newJWTToken(accessSecret string, refreshSecret string, username string) (map[string]string, error) {
 
-	token, err := GenerateSessionToken(accessSecret, 60*15)
+	token, err := GenerateSessionToken(accessSecret, 60*15, username)
 	if err != nil {
 		return nil, errors.New("can't genrated a access-token")
 	}
-	refreshToken, err := GenerateSessionToken(refreshSecret, 60*60*24*7)
+	refreshToken, err := GenerateSessionToken(refreshSecret, 60*60*24*7, username)
 	if err != nil {
 		return nil, errors.New("can't genrated a refresh-token")
 	}
@@ -145,6 +146,21 @@ func newJWTToken(accessSecret string, refreshSecret string) (map[string]string,
 
 }
 
+// username of login params (user : username, token : subject of a service-account token)
+func loginUsername(params map[string]string) string {
+
+	if params["username"] != "" {
+		return params["username"]
+	}
+	if claims, err := GetTokenClaims(params["token"]); err == nil {
+		if sub, ok := claims["sub"].(string); ok {
+			return sub
+		}
+	}
+	return ""
+
+}
+
 func BasicAuthAuthenticator(filename string, validateFunc ValidateFunc) *Authenticator {
 
 	h := &Authenticator{}
@@ -163,6 +179,7 @@ func BasicAuthAuthenticator(filename string, validateFunc ValidateFunc) *Authent
 				c.AbortWithStatus(http.StatusUnauthorized)
 				return
 			}
+			c.Set(ContextUsername, user)
 			c.Next()
 		}
 	}
@@ -25,11 +25,14 @@ func GetTokenClaims(token string) (jwt.MapClaims, error) {
 
 }
 
-// local auth token generate
-func GenerateSessionToken(secret string, second int) (string, error) {
+// local auth token generate (username : signed-in user, optional)
+func GenerateSessionToken(secret string, second int, username string) (string, error) {
 
 	claims := jwt.MapClaims{}
 	claims["expired_at"] = time.Now().Add(time.Second * time.Duration(second)).Unix()
+	if username != "" {
+		claims["username"] = username
+	}
 
 	signer := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	token, err := signer.SignedString([]byte(secret))
@@ -41,6 +44,18 @@ func GenerateSessionToken(secret string, second int) (string, error) {
 
 }
 
+// signed-in username of a local auth token (validated token only)
+func GetSessionUsername(token string) string {
+
+	claims, err := GetTokenClaims(token)
+	if err != nil {
+		return ""
+	}
+	username, _ := claims["username"].(string)
+	return username
+
+}
+
 // local auth token validate
 func ValidateSessionToken(secret string, token string) (expired bool, err error) {
 
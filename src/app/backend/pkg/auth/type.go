@@ -25,6 +25,7 @@ const (
 	SecretBasicAuth           = "basic-auth"
 	SecretStaticToken         = "static-token"
 	SecretServiceAccountToken = "service-account-token"
+	ContextUsername           = "auth.username" // gin context key of signed-in username
 )
 
 type AuthConfig struct {
@@ -41,6 +41,7 @@ type DynamicClient struct {
 	resource     schema.GroupVersionResource
 	namespace    string
 	namespaceSet bool
+	dryRun       []string
 }
 
 // RestfulClient 리턴
@@ -67,6 +68,15 @@ func (self *DynamicClient) SetNamespace(namespace string) {
 	self.namespaceSet = (namespace != "")
 }
 
+// DryRun (create, update)
+func (self *DynamicClient) SetDryRun(dryRun bool) {
+	if dryRun {
+		self.dryRun = []string{v1.DryRunAll}
+	} else {
+		self.dryRun = nil
+	}
+}
+
 // List
 func (self *DynamicClient) List(opts v1.ListOptions) (r *unstructured.UnstructuredList, err error) {
 
@@ -211,15 +221,15 @@ func (self *DynamicClient) POST(payload io.Reader, isUpdate bool) (output *unstr
 			}
 			data.SetResourceVersion(r.GetResourceVersion())
 			if resource.Namespaced {
-				output, err = dynamicClient.Resource(self.resource).Namespace(self.namespace).Update(context.TODO(), data, v1.UpdateOptions{})
+				output, err = dynamicClient.Resource(self.resource).Namespace(self.namespace).Update(context.TODO(), data, v1.UpdateOptions{DryRun: self.dryRun})
 			} else {
-				output, err = dynamicClient.Resource(self.resource).Update(context.TODO(), data, v1.UpdateOptions{})
+				output, err = dynamicClient.Resource(self.resource).Update(context.TODO(), data, v1.UpdateOptions{DryRun: self.dryRun})
 			}
 		} else {
 			if resource.Namespaced {
-				output, err = dynamicClient.Resource(self.resource).Namespace(self.namespace).Create(context.TODO(), data, v1.CreateOptions{})
+				output, err = dynamicClient.Resource(self.resource).Namespace(self.namespace).Create(context.TODO(), data, v1.CreateOptions{DryRun: self.dryRun})
 			} else {
-				output, err = dynamicClient.Resource(self.resource).Create(context.TODO(), data, v1.CreateOptions{})
+				output, err = dynamicClient.Resource(self.resource).Create(context.TODO(), data, v1.CreateOptions{DryRun: self.dryRun})
 			}
 		}
 
@@ -22,7 +22,8 @@ type CumulativeMetricsResourceSelector struct {
 	Node      string
 	Namespace string
 	Pods      []string
-	Function  string
+	Container string // pods only
+	Function  string // pods only (SUM, AVG, MAX, MIN)
 }
 
 type CumulativeMetricUnit struct {
@@ -33,7 +34,14 @@ type CumulativeMetricUnit struct {
 
 func (self *CumulativeMetricsResourceSelector) getUrl() string {
 	if len(self.Pods) > 0 {
-		return fmt.Sprintf("/namespaces/%s/pods/%s", self.Namespace, strings.Join(self.Pods, ","))
+		url := fmt.Sprintf("/namespaces/%s/pods/%s", self.Namespace, strings.Join(self.Pods, ","))
+		if self.Function != "" {
+			url = fmt.Sprintf("%s/%s", url, self.Function)
+		}
+		if self.Container != "" {
+			url = fmt.Sprintf("%s?container=%s", url, self.Container)
+		}
+		return url
 	} else if self.Node != "" {
 		return fmt.Sprintf("/nodes/%s", self.Node)
 	} else {
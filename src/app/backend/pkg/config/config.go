@@ -23,6 +23,10 @@ func init() {
 	flag.StringVar(&Value.TerminalUrl, "terminal-url", os.Getenv("TERMINAL_URL"), "The address of the Terminal server")
 	kubeconfig := flag.String("kubeconfig", "", "The path to the kubeconfig used to connect to the Kubernetes API server and the Kubelets")
 	authconfig := flag.String("auth", os.Getenv("AUTH"), "The authenticate options")
+	redactSecrets := flag.String("redact-secrets", os.Getenv("REDACT_SECRETS"), "Redact secret data in raw-api responses (true/false)")
+	redactConfigMapKeys := flag.String("redact-configmap-keys", os.Getenv("REDACT_CONFIGMAP_KEYS"), "Comma-separated configmap key patterns to redact in raw-api responses")
+	flag.StringVar(&Value.NodeShellKey, "node-shell-key", os.Getenv("NODE_SHELL_KEY"), "The key to sign node shell grants (shared with the terminal server, empty : node shell disabled)")
+	nodeShellUsers := flag.String("node-shell-users", os.Getenv("NODE_SHELL_USERS"), "Comma-separated signed-in usernames allowed to open a node shell")
 
 	//k8s.io client-go logs
 	flag.Set("logtostderr", "ture")
@@ -36,6 +40,17 @@ func init() {
 	Value.MetricsScraperUrl = lang.NVL(Value.MetricsScraperUrl, "http://localhost:8000")
 	Value.TerminalUrl = lang.NVL(Value.TerminalUrl, "http://localhost:3003")
 	*logLevel = lang.NVL(*logLevel, "debug")
+	Value.RedactSecrets = (lang.NVL(*redactSecrets, "true") != "false")
+	for _, k := range strings.Split(*redactConfigMapKeys, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			Value.RedactConfigMapKeys = append(Value.RedactConfigMapKeys, k)
+		}
+	}
+	for _, u := range strings.Split(*nodeShellUsers, ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			Value.NodeShellUsers = append(Value.NodeShellUsers, u)
+		}
+	}
 
 	//logger
 	log.SetFormatter(&log.TextFormatter{})
@@ -53,6 +68,8 @@ func init() {
 	log.Infof("Startup parameter 'metrics-scraper-url' is '%s'", Value.MetricsScraperUrl)
 	log.Infof("Startup parameter 'kubeconfig' is '%s'", *kubeconfig)
 	log.Infof("Startup parameter 'auth' is '%s'", *authconfig)
+	log.Infof("Startup parameter 'redact-secrets' is '%t', 'redact-configmap-keys' is '%v'", Value.RedactSecrets, Value.RedactConfigMapKeys)
+	log.Infof("Startup parameter 'node-shell-key' is set (node shell enabled) : %t, 'node-shell-users' is '%v'", Value.NodeShellKey != "", Value.NodeShellUsers)
 
 	// unmarshall kubeconfig
 	Value.KubeConfig = &kubeConfig{}
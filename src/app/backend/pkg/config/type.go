@@ -17,10 +17,14 @@ const (
 )
 
 type conf struct {
-	MetricsScraperUrl string           // metrics scraper
-	TerminalUrl       string           // terminal service Url
-	AuthConfig        *auth.AuthConfig // auth-config
-	KubeConfig        *kubeConfig      // kubeconfig file
+	MetricsScraperUrl   string           // metrics scraper
+	TerminalUrl         string           // terminal service Url
+	AuthConfig          *auth.AuthConfig // auth-config
+	KubeConfig          *kubeConfig      // kubeconfig file
+	RedactSecrets       bool             // redact secret data (raw-api)
+	RedactConfigMapKeys []string         // redact configmap keys (glob patterns)
+	NodeShellKey        string           // node shell grant signing key (shared with terminal service, empty : disabled)
+	NodeShellUsers      []string         // signed-in usernames allowed to open a node shell
 }
 
 type kubeConfig struct {
@@ -139,3 +139,75 @@ func hasPodReadyCondition(conditions []v1.PodCondition) bool {
 	}
 	return false
 }
+
+// pod health (severity)
+const (
+	HEALTH_OK      = "OK"
+	HEALTH_WARNING = "Warning"
+	HEALTH_ERROR   = "Error"
+)
+
+// pod health classified by a status (GetPodStatus) and container states, returns a severity (OK, Warning, Error), a reason and a message
+func GetPodHealth(pod v1.Pod) (string, string, string) {
+
+	reason := GetPodStatus(pod)
+	statuses := append(append([]v1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+
+	// OOMKilled (current or last termination) is more specific than CrashLoopBackOff
+	for _, container := range statuses {
+		if container.State.Terminated != nil && container.State.Terminated.Reason == "OOMKilled" {
+			return HEALTH_ERROR, "OOMKilled", fmt.Sprintf("container '%s' is killed by out of memory (limit=%s, restarts=%d)", container.Name, containerMemoryLimit(pod, container.Name), container.RestartCount)
+		} else if last := container.LastTerminationState.Terminated; last != nil && last.Reason == "OOMKilled" {
+			severity := HEALTH_WARNING // running again
+			if container.State.Waiting != nil {
+				severity = HEALTH_ERROR
+			}
+			return severity, "OOMKilled", fmt.Sprintf("container '%s' was killed by out of memory at %s (limit=%s, restarts=%d)", container.Name, last.FinishedAt.Format("2006-01-02 15:04:05"), containerMemoryLimit(pod, container.Name), container.RestartCount)
+		}
+	}
+
+	// waiting containers (CrashLoopBackOff, ImagePullBackOff, ...)
+	for _, container := range statuses {
+		if w := container.State.Waiting; w != nil {
+			switch w.Reason {
+			case "CrashLoopBackOff", "ImagePullBackOff", "ErrImagePull", "InvalidImageName", "CreateContainerConfigError", "CreateContainerError", "RunContainerError":
+				return HEALTH_ERROR, w.Reason, fmt.Sprintf("container '%s' : %s", container.Name, NVL(w.Message, fmt.Sprintf("restarts=%d", container.RestartCount)))
+			}
+		}
+	}
+
+	switch pod.Status.Phase {
+	case v1.PodSucceeded:
+		return HEALTH_OK, reason, ""
+	case v1.PodFailed:
+		return HEALTH_ERROR, reason, pod.Status.Message
+	case v1.PodPending:
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == v1.PodScheduled && condition.Status == v1.ConditionFalse && condition.Reason == v1.PodReasonUnschedulable {
+				return HEALTH_ERROR, v1.PodReasonUnschedulable, condition.Message
+			}
+		}
+		return HEALTH_WARNING, reason, pod.Status.Message
+	case v1.PodUnknown:
+		return HEALTH_ERROR, reason, pod.Status.Message
+	}
+
+	if pod.DeletionTimestamp != nil {
+		return HEALTH_OK, reason, ""
+	}
+	if !hasPodReadyCondition(pod.Status.Conditions) {
+		return HEALTH_WARNING, "NotReady", fmt.Sprintf("%s containers are ready", GetPodReady(pod))
+	}
+	return HEALTH_OK, reason, ""
+
+}
+
+// memory limit of a container ("none" if not specified)
+func containerMemoryLimit(pod v1.Pod, name string) string {
+	for _, c := range append(append([]v1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...) {
+		if q, ok := c.Resources.Limits[v1.ResourceMemory]; c.Name == name && ok {
+			return q.String()
+		}
+	}
+	return "none"
+}
@@ -0,0 +1,51 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kore3lab/dashboard/model"
+	"github.com/kore3lab/dashboard/pkg/app"
+	"github.com/kore3lab/dashboard/pkg/config"
+	"github.com/kore3lab/dashboard/pkg/lang"
+)
+
+// Review accesses ("can-i") of the current user or a subject
+func ReviewAccess(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	req := model.AccessReviewRequest{}
+	if g.C.BindJSON(&req) != nil {
+		g.SendMessage(http.StatusBadRequest, "Unable to bind request body", nil)
+		return
+	}
+
+	if results, err := model.ReviewAccess(lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext), req); err != nil {
+		g.SendError(err)
+	} else {
+		g.Send(http.StatusOK, results)
+	}
+
+}
+
+// Get effective rules of the current user or a subject (kind, name, namespace, group query parameters)
+func ReviewRules(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	var subject *model.AccessSubject
+	if g.C.Query("name") != "" {
+		subject = &model.AccessSubject{
+			Kind:      g.C.Query("kind"),
+			Name:      g.C.Query("name"),
+			Namespace: g.C.Query("namespace"),
+			Groups:    g.C.QueryArray("group"),
+		}
+	}
+
+	if result, err := model.ReviewRules(lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext), lang.NVL(g.C.Param("NAMESPACE"), "default"), subject); err != nil {
+		g.SendError(err)
+	} else {
+		g.Send(http.StatusOK, result)
+	}
+
+}
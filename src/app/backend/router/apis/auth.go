@@ -6,6 +6,7 @@ import (
 
 	"github.com/kore3lab/dashboard/pkg/app"
 	"github.com/kore3lab/dashboard/pkg/auth"
+	"github.com/kore3lab/dashboard/pkg/lang"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/gin-gonic/gin"
@@ -92,15 +93,7 @@ func RefreshToken(c *gin.Context) {
 func GetUser(c *gin.Context) {
 	g := app.Gin{C: c}
 
-	scheme := config.Value.AuthConfig.GetSchema()
-
-	user := &user{}
-
-	if scheme == "user" {
-		user.Username = config.Value.AuthConfig.Data["username"]
-	} else {
-		user.Username = "admin"
-	}
+	user := &user{Username: lang.NVL(getUsername(c), "admin")}
 
 	g.Send(http.StatusOK, map[string]interface{}{
 		"user": user,
@@ -108,6 +101,18 @@ func GetUser(c *gin.Context) {
 
 }
 
+// signed-in username (empty : unidentified)
+func getUsername(c *gin.Context) string {
+
+	if username := c.GetString(auth.ContextUsername); username != "" {
+		return username
+	} else if config.Value.AuthConfig.Secret == auth.SecretStaticUser {
+		return config.Value.AuthConfig.Data["username"]
+	}
+	return ""
+
+}
+
 // sign-out
 func Logout(c *gin.Context) {
 	g := app.Gin{C: c}
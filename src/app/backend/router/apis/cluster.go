@@ -1,14 +1,17 @@
 package apis
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/kore3lab/dashboard/model"
 	"github.com/kore3lab/dashboard/pkg/app"
 	"github.com/kore3lab/dashboard/pkg/config"
 	"github.com/kore3lab/dashboard/pkg/lang"
-	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 )
 
 func Network(c *gin.Context) {
@@ -20,21 +23,41 @@ func Network(c *gin.Context) {
 	if topology, err := model.GetNetworkGraph(cluster, namespace); err != nil {
 		g.SendError(err)
 	} else {
-		g.Send(http.StatusOK, topology)
+		sendGraph(g, "network", topology)
 	}
 
 }
 
+// topology graph ("?metrics=true" : usage, utilization and health of pods and nodes)
 func Topology(c *gin.Context) {
 	g := app.Gin{C: c}
 
 	cluster := lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext)
 	namespace := c.Param("NAMESPACE")
 
-	if topology, err := model.GetTopologyGraph(cluster, namespace); err != nil {
+	if topology, err := model.GetTopologyGraph(cluster, namespace, c.Query("metrics") == "true"); err != nil {
 		g.SendError(err)
 	} else {
-		g.Send(http.StatusOK, topology)
+		sendGraph(g, "topology", topology)
+	}
+
+}
+
+// dependency graph (kind, namespace, name query parameters : blast radius of an object, namespace defaults to the graph's)
+func Dependency(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	cluster := lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext)
+	namespace := c.Param("NAMESPACE")
+
+	if topology, err := model.GetDependencyGraph(cluster, namespace, c.Query("kind"), lang.NVL(c.Query("namespace"), namespace), c.Query("name")); errors.IsBadRequest(err) {
+		g.SendMessage(http.StatusBadRequest, err.Error(), err)
+	} else if errors.IsNotFound(err) {
+		g.SendMessage(http.StatusNotFound, err.Error(), err)
+	} else if err != nil {
+		g.SendError(err)
+	} else {
+		sendGraph(g, "dependency", topology)
 	}
 
 }
@@ -48,7 +71,22 @@ func Workloads(c *gin.Context) {
 	if workloads, err := model.GetWorkloadGraph(cluster, namespace); err != nil {
 		g.SendError(err)
 	} else {
-		g.Send(http.StatusOK, workloads)
+		sendGraph(g, "workloads", workloads)
+	}
+
+}
+
+// storage graph (storageclasses, persistentvolumes, persistentvolumeclaims and consuming pods)
+func Storage(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	cluster := lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext)
+	namespace := c.Param("NAMESPACE")
+
+	if storage, err := model.GetStorageGraph(cluster, namespace); err != nil {
+		g.SendError(err)
+	} else {
+		sendGraph(g, "storage", storage)
 	}
 
 }
@@ -63,70 +101,139 @@ func Pod(c *gin.Context) {
 	if workloads, err := model.GetPodGraph(cluster, namespace, name); err != nil {
 		g.SendError(err)
 	} else {
-		g.Send(http.StatusOK, workloads)
+		sendGraph(g, "pod", workloads)
 	}
 
 }
 
-func Dashboard(c *gin.Context) {
+func RBAC(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	cluster := lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext)
+	namespace := c.Param("NAMESPACE")
+
+	if topology, err := model.GetRBACGraph(cluster, namespace); err != nil {
+		g.SendError(err)
+	} else {
+		sendGraph(g, "rbac", topology)
+	}
+
+}
+
+// who can <verb> <resource> (verb, resource, group, subresource, name, path query parameters)
+func WhoCan(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	cluster := lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext)
+	namespace := c.Param("NAMESPACE")
+	attr := model.AccessAttributes{
+		Verb:        c.Query("verb"),
+		Group:       c.Query("group"),
+		Resource:    c.Query("resource"),
+		Subresource: c.Query("subresource"),
+		Name:        c.Query("name"),
+		Namespace:   namespace,
+		Path:        c.Query("path"),
+	}
+
+	if topology, err := model.GetWhoCanGraph(cluster, namespace, attr); err != nil {
+		g.SendMessage(http.StatusBadRequest, err.Error(), err)
+	} else {
+		sendGraph(g, "whocan", topology)
+	}
+
+}
+
+// network-policy reachability graph (pods -> reachable pods, ipBlocks)
+func NetworkPolicy(c *gin.Context) {
 	g := app.Gin{C: c}
 
 	cluster := lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext)
+	namespace := c.Param("NAMESPACE")
 
-	clientSet, err := config.Cluster.Client(cluster)
-	if err != nil {
+	if hierarchy, err := model.GetNetworkPolicyGraph(cluster, namespace); errors.IsBadRequest(err) {
+		g.SendMessage(http.StatusBadRequest, err.Error(), err)
+	} else if err != nil {
 		g.SendError(err)
+	} else {
+		sendGraph(g, "networkpolicy", hierarchy)
+	}
+
+}
+
+// can A talk to B on port P (from, to, port, protocol query parameters)
+func NetworkPolicyCheck(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	cluster := lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext)
+
+	if c.Query("from") == "" || c.Query("to") == "" {
+		g.SendMessage(http.StatusBadRequest, "Required parameters 'from', 'to'", nil)
 		return
 	}
+	port := 0
+	if q := c.Query("port"); q != "" {
+		var err error
+		if port, err = strconv.Atoi(q); err != nil {
+			g.SendMessage(http.StatusBadRequest, "Invalid parameter 'port'", err)
+			return
+		}
+	}
+
+	if result, err := model.GetReachability(cluster, c.Query("from"), c.Query("to"), int32(port), c.Query("protocol")); errors.IsBadRequest(err) {
+		g.SendMessage(http.StatusBadRequest, err.Error(), err)
+	} else if err != nil {
+		g.SendError(err)
+	} else {
+		g.Send(http.StatusOK, result)
+	}
+
+}
+
+// unhealthy workloads (pods, jobs, deployments, statefulsets and nodes)
+func Problems(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	cluster := lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext)
+	namespace := c.Param("NAMESPACE")
+
+	if problems, err := model.GetProblems(cluster, namespace); err != nil {
+		g.SendError(err)
+	} else {
+		g.Send(http.StatusOK, problems)
+	}
+
+}
+
+// dashboard summary (workloads, jobs, storage, services, namespaces and warning events)
+func Dashboard(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	cluster := lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext)
+	namespace := c.Param("NAMESPACE")
 
-	apiClient, err := clientSet.NewKubernetesClient()
-	if err != nil {
+	if summary, err := model.GetDashboardSummary(cluster, namespace); err != nil {
 		g.SendError(err)
+	} else {
+		g.Send(http.StatusOK, summary)
+	}
+
+}
+
+// send a graph as json or a text format ("format" query parameter : json, dot, graphml, mermaid)
+func sendGraph(g app.Gin, name string, graph model.Graph) {
+
+	format := strings.ToLower(lang.NVL(g.C.Query("format"), model.GRAPH_FORMAT_JSON))
+	if format == model.GRAPH_FORMAT_JSON {
+		g.Send(http.StatusOK, graph)
 		return
 	}
 
-	timeout := int64(5)
-	options := metaV1.ListOptions{TimeoutSeconds: &timeout} //timeout 5s
-
-	workloads := map[string]struct {
-		Ready     int `json:"ready"`
-		Available int `json:"available"`
-	}{}
-
-	// daemonset
-	available, ready, _ := model.GetDaemonSetsReady(apiClient, options)
-	workloads["daemonset"] = struct {
-		Ready     int `json:"ready"`
-		Available int `json:"available"`
-	}{Available: available, Ready: ready}
-
-	// deployment
-	available, ready, _ = model.GetDeploymentsReady(apiClient, options)
-	workloads["deployment"] = struct {
-		Ready     int `json:"ready"`
-		Available int `json:"available"`
-	}{Available: available, Ready: ready}
-
-	// replicaset
-	available, ready, _ = model.GetReplicaSetsReady(apiClient, options)
-	workloads["replicaset"] = struct {
-		Ready     int `json:"ready"`
-		Available int `json:"available"`
-	}{Available: available, Ready: ready}
-
-	// statefulset
-	available, ready, _ = model.GetStatefulSetsReady(apiClient, options)
-	workloads["statefulset"] = struct {
-		Ready     int `json:"ready"`
-		Available int `json:"available"`
-	}{Available: available, Ready: ready}
-
-	// pods
-	available, ready, _ = model.GetPodsReady(apiClient, options)
-	workloads["pod"] = struct {
-		Ready     int `json:"ready"`
-		Available int `json:"available"`
-	}{Available: available, Ready: ready}
-
-	g.Send(http.StatusOK, workloads)
+	if data, err := graph.Export(format); err != nil {
+		g.SendMessage(http.StatusBadRequest, err.Error(), err)
+	} else {
+		g.C.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.%s", name, model.GraphFormats[format].Extension))
+		g.C.Data(http.StatusOK, model.GraphFormats[format].ContentType, data)
+	}
+
 }
@@ -0,0 +1,32 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kore3lab/dashboard/model"
+	"github.com/kore3lab/dashboard/pkg/app"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Compare a resource across clusters or namespaces
+func Compare(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	req := model.CompareRequest{}
+	if g.C.BindJSON(&req) != nil {
+		g.SendMessage(http.StatusBadRequest, "Unable to bind request body", nil)
+		return
+	}
+
+	if result, err := model.GetCompare(req); errors.IsBadRequest(err) {
+		g.SendMessage(http.StatusBadRequest, err.Error(), err)
+	} else if errors.IsNotFound(err) {
+		g.SendMessage(http.StatusNotFound, err.Error(), err)
+	} else if err != nil {
+		g.SendError(err)
+	} else {
+		g.Send(http.StatusOK, result)
+	}
+
+}
@@ -0,0 +1,30 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kore3lab/dashboard/model"
+	"github.com/kore3lab/dashboard/pkg/app"
+)
+
+// Copy (promote) resources between clusters or namespaces
+func Copy(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	req := model.CopyRequest{}
+	if g.C.BindJSON(&req) != nil {
+		g.SendMessage(http.StatusBadRequest, "Unable to bind request body", nil)
+		return
+	}
+
+	if results, err := model.CopyResources(req); err != nil {
+		g.SendMessage(http.StatusBadRequest, err.Error(), err)
+	} else {
+		g.Send(http.StatusOK, map[string]interface{}{
+			"dryRun":  req.DryRun,
+			"results": results,
+		})
+	}
+
+}
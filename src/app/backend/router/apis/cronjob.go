@@ -0,0 +1,84 @@
+package apis
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kore3lab/dashboard/model"
+	"github.com/kore3lab/dashboard/pkg/app"
+	"github.com/kore3lab/dashboard/pkg/config"
+	"github.com/kore3lab/dashboard/pkg/lang"
+)
+
+// Create a job from a cronjob template immediately ("run now")
+func RunCronJob(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	if !validateCronJobUrl(g) {
+		return
+	}
+
+	if run, err := model.RunCronJob(lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext), c.Param("NAMESPACE"), c.Param("NAME")); err != nil {
+		g.SendError(err)
+	} else {
+		g.Send(http.StatusCreated, run)
+	}
+
+}
+
+// Suspend a cronjob
+func SuspendCronJob(c *gin.Context) {
+	suspendCronJob(c, true)
+}
+
+// Resume a cronjob
+func ResumeCronJob(c *gin.Context) {
+	suspendCronJob(c, false)
+}
+
+// Get jobs owned by a cronjob (latest first)
+func GetCronJobHistory(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	if !validateCronJobUrl(g) {
+		return
+	}
+
+	if runs, err := model.GetCronJobHistory(lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext), c.Param("NAMESPACE"), c.Param("NAME")); err != nil {
+		g.SendError(err)
+	} else {
+		g.Send(http.StatusOK, runs)
+	}
+
+}
+
+func suspendCronJob(c *gin.Context, suspend bool) {
+	g := app.Gin{C: c}
+
+	if !validateCronJobUrl(g) {
+		return
+	}
+
+	if cronjob, err := model.SuspendCronJob(lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext), c.Param("NAMESPACE"), c.Param("NAME"), suspend); err != nil {
+		g.SendError(err)
+	} else {
+		g.Send(http.StatusOK, cronjob)
+	}
+
+}
+
+// validate url parameters (namespace, resource "cronjobs", name)
+func validateCronJobUrl(g app.Gin) bool {
+
+	if err := g.ValidateUrl([]string{"NAMESPACE", "RESOURCE", "NAME"}); err != nil {
+		g.SendMessage(http.StatusBadRequest, err.Error(), err)
+		return false
+	}
+	if g.C.Param("RESOURCE") != "cronjobs" {
+		g.SendMessage(http.StatusBadRequest, fmt.Sprintf("unsupported resource '%s'", g.C.Param("RESOURCE")), nil)
+		return false
+	}
+	return true
+
+}
@@ -0,0 +1,98 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kore3lab/dashboard/model"
+	"github.com/kore3lab/dashboard/pkg/app"
+	"github.com/kore3lab/dashboard/pkg/config"
+	"github.com/kore3lab/dashboard/pkg/lang"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Onboard a user (generate a key & a csr, submit and approve when allowed)
+func CreateUserCertificate(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	req := model.UserCertificateRequest{}
+	if g.C.BindJSON(&req) != nil {
+		g.SendMessage(http.StatusBadRequest, "Unable to bind request body", nil)
+		return
+	}
+
+	if result, err := model.CreateUserCertificate(lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext), req); err != nil {
+		g.SendError(err)
+	} else {
+		c.Header("Cache-Control", "no-store")
+		g.Send(http.StatusOK, result)
+	}
+
+}
+
+// Get a kubeconfig of an issued csr (key : private key generated on the request)
+func GetUserCertificateKubeconfig(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	req := struct {
+		Key       string `json:"key"`
+		Namespace string `json:"namespace"`
+	}{}
+	if g.C.BindJSON(&req) != nil || req.Key == "" {
+		g.SendMessage(http.StatusBadRequest, "Unable to bind request body", nil)
+		return
+	}
+
+	if kubeconfig, err := model.GetUserCertificateKubeconfig(lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext), c.Param("NAME"), req.Key, req.Namespace); errors.IsBadRequest(err) {
+		g.SendMessage(http.StatusBadRequest, err.Error(), err)
+	} else if errors.IsNotFound(err) {
+		g.SendMessage(http.StatusNotFound, err.Error(), err)
+	} else if err != nil {
+		g.SendError(err)
+	} else {
+		c.Header("Cache-Control", "no-store")
+		g.Send(http.StatusOK, map[string]string{"kubeconfig": kubeconfig})
+	}
+
+}
+
+// Get certificate signing requests (status : Pending, Approved, Denied, Failed, Issued)
+func GetCertificateSigningRequests(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	if list, err := model.GetCertificateSigningRequests(lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext), c.Query("status")); err != nil {
+		g.SendError(err)
+	} else {
+		g.Send(http.StatusOK, list)
+	}
+
+}
+
+// Approve a certificate signing request
+func ApproveCertificateSigningRequest(c *gin.Context) {
+	updateCertificateSigningRequestApproval(c, true)
+}
+
+// Deny a certificate signing request
+func DenyCertificateSigningRequest(c *gin.Context) {
+	updateCertificateSigningRequestApproval(c, false)
+}
+
+func updateCertificateSigningRequestApproval(c *gin.Context, approve bool) {
+	g := app.Gin{C: c}
+
+	req := struct {
+		Message string `json:"message"`
+	}{}
+	if c.Request.ContentLength > 0 && g.C.BindJSON(&req) != nil {
+		g.SendMessage(http.StatusBadRequest, "Unable to bind request body", nil)
+		return
+	}
+
+	if csr, err := model.ApproveCertificateSigningRequest(lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext), c.Param("NAME"), approve, req.Message); err != nil {
+		g.SendError(err)
+	} else {
+		g.Send(http.StatusOK, csr)
+	}
+
+}
@@ -0,0 +1,39 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kore3lab/dashboard/model"
+	"github.com/kore3lab/dashboard/pkg/app"
+	"github.com/kore3lab/dashboard/pkg/config"
+	"github.com/kore3lab/dashboard/pkg/lang"
+)
+
+// Get custom resource definition list
+func GetCustomResourceDefinitions(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	cluster := lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext)
+
+	if crds, err := model.GetCustomResourceDefinitions(cluster); err != nil {
+		g.SendError(err)
+	} else {
+		g.Send(http.StatusOK, crds)
+	}
+
+}
+
+// Get custom resource list (printer columns, status conditions)
+func GetCustomResources(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	cluster := lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext)
+
+	if table, err := model.GetCustomResourceTable(cluster, c.Param("CRD"), c.Param("NAMESPACE")); err != nil {
+		g.SendError(err)
+	} else {
+		g.Send(http.StatusOK, table)
+	}
+
+}
@@ -0,0 +1,40 @@
+package apis
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kore3lab/dashboard/model"
+	"github.com/kore3lab/dashboard/pkg/app"
+	"github.com/kore3lab/dashboard/pkg/config"
+	"github.com/kore3lab/dashboard/pkg/lang"
+)
+
+// Inject an ephemeral debug container into a running pod
+func CreateDebugContainer(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	v := []string{"NAMESPACE", "RESOURCE", "NAME"}
+	if err := g.ValidateUrl(v); err != nil {
+		g.SendMessage(http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	if c.Param("RESOURCE") != "pods" {
+		g.SendMessage(http.StatusBadRequest, fmt.Sprintf("unsupported resource '%s'", c.Param("RESOURCE")), nil)
+		return
+	}
+
+	req := model.DebugContainerRequest{}
+	if c.Request.ContentLength > 0 && g.C.BindJSON(&req) != nil {
+		g.SendMessage(http.StatusBadRequest, "Unable to bind request body", nil)
+		return
+	}
+
+	if debug, err := model.CreateDebugContainer(lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext), c.Param("NAMESPACE"), c.Param("NAME"), req); err != nil {
+		g.SendError(err)
+	} else {
+		g.Send(http.StatusOK, debug)
+	}
+
+}
@@ -0,0 +1,17 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kore3lab/dashboard/model"
+	"github.com/kore3lab/dashboard/pkg/app"
+)
+
+// health summary of all contexts (cached, "?refresh=true" to reload)
+func GetFleet(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	g.Send(http.StatusOK, model.GetFleet(c.Query("refresh") == "true"))
+
+}
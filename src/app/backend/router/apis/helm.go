@@ -0,0 +1,64 @@
+package apis
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kore3lab/dashboard/model"
+	"github.com/kore3lab/dashboard/pkg/app"
+	"github.com/kore3lab/dashboard/pkg/config"
+	"github.com/kore3lab/dashboard/pkg/lang"
+)
+
+// Get helm release list (latest revisions)
+func GetHelmReleases(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	cluster := lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext)
+
+	if releases, err := model.GetHelmReleases(cluster, c.Param("NAMESPACE")); err != nil {
+		g.SendError(err)
+	} else {
+		g.Send(http.StatusOK, releases)
+	}
+
+}
+
+// Get helm release revision history
+func GetHelmReleaseHistory(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	cluster := lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext)
+
+	if history, err := model.GetHelmReleaseHistory(cluster, c.Param("NAMESPACE"), c.Param("NAME")); err != nil {
+		g.SendMessage(http.StatusNotFound, err.Error(), err)
+	} else {
+		g.Send(http.StatusOK, history)
+	}
+
+}
+
+// Get a helm release revision (manifest, values)
+func GetHelmRelease(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	cluster := lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext)
+
+	// revision (empty : latest)
+	revision := 0
+	if c.Param("REVISION") != "" {
+		var err error
+		if revision, err = strconv.Atoi(c.Param("REVISION")); err != nil {
+			g.SendMessage(http.StatusBadRequest, err.Error(), err)
+			return
+		}
+	}
+
+	if release, err := model.GetHelmRelease(cluster, c.Param("NAMESPACE"), c.Param("NAME"), revision); err != nil {
+		g.SendMessage(http.StatusNotFound, err.Error(), err)
+	} else {
+		g.Send(http.StatusOK, release)
+	}
+
+}
@@ -0,0 +1,209 @@
+package apis
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kore3lab/dashboard/model"
+	"github.com/kore3lab/dashboard/pkg/app"
+	"github.com/kore3lab/dashboard/pkg/config"
+	"github.com/kore3lab/dashboard/pkg/lang"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// a key value (value is base64 encoded if "base64" is true)
+type keyValueRequest struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Base64 bool   `json:"base64"`
+}
+
+// Add keys (json or multipart files, fails if a key exists)
+func AddKeys(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	v := []string{"NAMESPACE", "RESOURCE", "NAME"}
+	if err := g.ValidateUrl(v); err != nil {
+		g.SendMessage(http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	values, err := bindKeyValues(c, "")
+	if err != nil {
+		g.SendMessage(http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	r, err := model.SetKeys(lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext), c.Param("NAMESPACE"), c.Param("RESOURCE"), c.Param("NAME"), values, false)
+	if err != nil {
+		sendKeyError(g, err)
+		return
+	}
+	g.Send(http.StatusOK, r)
+
+}
+
+// Add or replace a key (json or a multipart file)
+func PutKey(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	v := []string{"NAMESPACE", "RESOURCE", "NAME", "KEY"}
+	if err := g.ValidateUrl(v); err != nil {
+		g.SendMessage(http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	values, err := bindKeyValues(c, c.Param("KEY"))
+	if err != nil {
+		g.SendMessage(http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	r, err := model.SetKeys(lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext), c.Param("NAMESPACE"), c.Param("RESOURCE"), c.Param("NAME"), values, true)
+	if err != nil {
+		sendKeyError(g, err)
+		return
+	}
+	g.Send(http.StatusOK, r)
+
+}
+
+// Rename a key
+func RenameKey(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	v := []string{"NAMESPACE", "RESOURCE", "NAME", "KEY"}
+	if err := g.ValidateUrl(v); err != nil {
+		g.SendMessage(http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	req := struct {
+		Name string `json:"name"`
+	}{}
+	if err := g.C.BindJSON(&req); err != nil {
+		g.SendMessage(http.StatusBadRequest, "Unable to bind request body", err)
+		return
+	}
+	if req.Name == "" {
+		g.SendMessage(http.StatusBadRequest, "a new key name is empty", nil)
+		return
+	}
+
+	r, err := model.RenameKey(lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext), c.Param("NAMESPACE"), c.Param("RESOURCE"), c.Param("NAME"), c.Param("KEY"), req.Name)
+	if err != nil {
+		sendKeyError(g, err)
+		return
+	}
+	g.Send(http.StatusOK, r)
+
+}
+
+// Delete a key
+func DeleteKey(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	v := []string{"NAMESPACE", "RESOURCE", "NAME", "KEY"}
+	if err := g.ValidateUrl(v); err != nil {
+		g.SendMessage(http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	r, err := model.DeleteKey(lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext), c.Param("NAMESPACE"), c.Param("RESOURCE"), c.Param("NAME"), c.Param("KEY"))
+	if err != nil {
+		sendKeyError(g, err)
+		return
+	}
+	g.Send(http.StatusOK, r)
+
+}
+
+// send 4xx status errors (invalid key, conflict, not found) with their status codes
+func sendKeyError(g app.Gin, err error) {
+	if status, ok := err.(errors.APIStatus); ok && status.Status().Code >= 400 && status.Status().Code < 500 {
+		g.SendMessage(int(status.Status().Code), err.Error(), err)
+	} else {
+		g.SendError(err)
+	}
+}
+
+// key values from a request body (multipart files : key = form "key" or a file name, json : a key value or key values array)
+func bindKeyValues(c *gin.Context, key string) (map[string][]byte, error) {
+
+	values := map[string][]byte{}
+
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		form, err := c.MultipartForm()
+		if err != nil {
+			return nil, err
+		}
+		files := []*multipart.FileHeader{}
+		for _, f := range form.File {
+			files = append(files, f...)
+		}
+		if key == "" && len(form.Value["key"]) > 0 {
+			key = form.Value["key"][0]
+		}
+		if key != "" && len(files) > 1 {
+			return nil, fmt.Errorf("only one file is allowed for key '%s'", key)
+		}
+		for _, f := range files {
+			b, err := readMultipartFile(f)
+			if err != nil {
+				return nil, err
+			}
+			values[lang.NVL(key, f.Filename)] = b
+		}
+	} else {
+		body, err := c.GetRawData()
+		if err != nil {
+			return nil, err
+		}
+		req := []keyValueRequest{}
+		if err := json.Unmarshal(body, &req); err != nil {
+			kv := keyValueRequest{}
+			if err := json.Unmarshal(body, &kv); err != nil {
+				return nil, fmt.Errorf("Unable to bind request body")
+			}
+			req = append(req, kv)
+		}
+		if key != "" {
+			if len(req) != 1 {
+				return nil, fmt.Errorf("only one value is allowed for key '%s'", key)
+			}
+			req[0].Key = key
+		}
+		for _, kv := range req {
+			if kv.Base64 {
+				b, err := base64.StdEncoding.DecodeString(kv.Value)
+				if err != nil {
+					return nil, fmt.Errorf("invalid base64 value of key '%s'", kv.Key)
+				}
+				values[kv.Key] = b
+			} else {
+				values[kv.Key] = []byte(kv.Value)
+			}
+		}
+	}
+
+	if len(values) == 0 {
+		return nil, fmt.Errorf("keys are empty")
+	}
+	return values, nil
+
+}
+
+func readMultipartFile(f *multipart.FileHeader) ([]byte, error) {
+	r, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
@@ -0,0 +1,43 @@
+package apis
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kore3lab/dashboard/model"
+	"github.com/kore3lab/dashboard/pkg/app"
+	"github.com/kore3lab/dashboard/pkg/config"
+	"github.com/kore3lab/dashboard/pkg/lang"
+)
+
+// Create (or reuse) a service account and returns a kubeconfig with a time-bound token
+func CreateServiceAccountKubeconfig(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	v := []string{"NAMESPACE", "RESOURCE", "NAME"}
+	if err := g.ValidateUrl(v); err != nil {
+		g.SendMessage(http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	if c.Param("RESOURCE") != "serviceaccounts" {
+		g.SendMessage(http.StatusBadRequest, fmt.Sprintf("unsupported resource '%s'", c.Param("RESOURCE")), nil)
+		return
+	}
+
+	req := model.ServiceAccountKubeconfigRequest{}
+	if g.C.BindJSON(&req) != nil {
+		g.SendMessage(http.StatusBadRequest, "Unable to bind request body", nil)
+		return
+	}
+	req.Namespace = c.Param("NAMESPACE")
+	req.Name = c.Param("NAME")
+
+	if kubeconfig, err := model.CreateServiceAccountKubeconfig(lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext), req); err != nil {
+		g.SendError(err)
+	} else {
+		c.Header("Cache-Control", "no-store")
+		g.Send(http.StatusOK, kubeconfig)
+	}
+
+}
@@ -4,6 +4,7 @@ import (
 	//"errors"
 	//"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/kore3lab/dashboard/model"
@@ -57,6 +58,36 @@ func GetWorkloadMetrics(c *gin.Context) {
 
 }
 
+// Get right-sizing recommendations of a workload (pod, deployments, statefulsets, daemonsets, replicasets, jobs, cronjobs), "?percentile=95&headroom=0.15"
+func GetWorkloadRightSizing(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	cluster := lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext)
+
+	percentile, headroom := 0.0, 0.0
+	var err error
+	if q := c.Query("percentile"); q != "" {
+		if percentile, err = strconv.ParseFloat(q, 64); err != nil {
+			g.SendMessage(http.StatusBadRequest, "Invalid parameter 'percentile'", err)
+			return
+		}
+	}
+	if q := c.Query("headroom"); q != "" {
+		if headroom, err = strconv.ParseFloat(q, 64); err != nil {
+			g.SendMessage(http.StatusBadRequest, "Invalid parameter 'headroom'", err)
+			return
+		}
+	}
+
+	result, err := model.GetRightSizing(cluster, c.Param("NAMESPACE"), c.Param("RESOURCE"), c.Param("NAME"), percentile, headroom)
+	if err != nil {
+		g.SendError(err)
+	} else {
+		g.Send(http.StatusOK, result)
+	}
+
+}
+
 // Get node list
 func GetNodeListWithUsage(c *gin.Context) {
 	g := app.Gin{C: c}
@@ -0,0 +1,35 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kore3lab/dashboard/model"
+	"github.com/kore3lab/dashboard/pkg/app"
+	"github.com/kore3lab/dashboard/pkg/config"
+	"github.com/kore3lab/dashboard/pkg/lang"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Issue a grant to open a node shell on the terminal service (admin only)
+func CreateNodeShellGrant(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	if err := g.ValidateUrl([]string{"NAME"}); err != nil {
+		g.SendMessage(http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	grant, err := model.CreateNodeShellGrant(lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext), c.Param("NAME"), getUsername(c))
+	if errors.IsForbidden(err) {
+		g.SendMessage(http.StatusForbidden, err.Error(), err)
+	} else if errors.IsNotFound(err) {
+		g.SendMessage(http.StatusNotFound, err.Error(), err)
+	} else if err != nil {
+		g.SendError(err)
+	} else {
+		c.Header("Cache-Control", "no-store")
+		g.Send(http.StatusOK, grant)
+	}
+
+}
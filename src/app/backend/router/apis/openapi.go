@@ -0,0 +1,94 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kore3lab/dashboard/model"
+	"github.com/kore3lab/dashboard/pkg/app"
+	"github.com/kore3lab/dashboard/pkg/config"
+	"github.com/kore3lab/dashboard/pkg/lang"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Get openapi v3 paths (group versions)
+func GetOpenAPIPaths(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	cluster := lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext)
+
+	if paths, err := model.GetOpenAPIPaths(cluster); err != nil {
+		g.SendError(err)
+	} else {
+		g.Send(http.StatusOK, map[string]interface{}{
+			"paths": paths,
+		})
+	}
+
+}
+
+// Get openapi v3 schema of a group version (querystring : apiVersion)
+func GetOpenAPISchema(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	cluster := lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext)
+	if g.C.Query("apiVersion") == "" {
+		g.SendMessage(http.StatusBadRequest, "apiVersion is required", nil)
+		return
+	}
+
+	if schema, err := model.GetOpenAPISchema(cluster, g.C.Query("apiVersion")); errors.IsNotFound(err) {
+		g.SendMessage(http.StatusNotFound, err.Error(), err)
+	} else if err != nil {
+		g.SendError(err)
+	} else {
+		g.Send(http.StatusOK, schema)
+	}
+
+}
+
+// Explain a field (querystring : apiVersion, kind, field)
+func ExplainField(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	cluster := lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext)
+	if g.C.Query("apiVersion") == "" || g.C.Query("kind") == "" {
+		g.SendMessage(http.StatusBadRequest, "apiVersion and kind are required", nil)
+		return
+	}
+
+	if explanation, err := model.ExplainField(cluster, g.C.Query("apiVersion"), g.C.Query("kind"), g.C.Query("field")); errors.IsNotFound(err) {
+		g.SendMessage(http.StatusNotFound, err.Error(), err)
+	} else if err != nil {
+		g.SendError(err)
+	} else {
+		g.Send(http.StatusOK, explanation)
+	}
+
+}
+
+// Validate manifests (yaml or json)
+func ValidateManifest(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	cluster := lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext)
+
+	results, err := model.ValidateManifest(cluster, g.C.Request.Body)
+	if errors.IsBadRequest(err) {
+		g.SendMessage(http.StatusBadRequest, err.Error(), err)
+		return
+	} else if err != nil {
+		g.SendError(err)
+		return
+	}
+
+	valid := true
+	for _, r := range results {
+		valid = valid && r.Valid
+	}
+	g.Send(http.StatusOK, map[string]interface{}{
+		"valid":   valid,
+		"results": results,
+	})
+
+}
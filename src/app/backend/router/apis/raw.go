@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/kore3lab/dashboard/model"
 	"github.com/kore3lab/dashboard/pkg/app"
 	"github.com/kore3lab/dashboard/pkg/config"
 	log "github.com/sirupsen/logrus"
@@ -67,12 +68,20 @@ func ApplyRaw(c *gin.Context) {
 		return
 	}
 
+	// restore redacted values
+	payload, err := model.RestoreRedacted(g.C.Param("CLUSTER"), g.C.Request.Body)
+	if err != nil {
+		g.SendMessage(http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
 	// invoke POST
-	r, err := api.POST(g.C.Request.Body, g.C.Request.Method == "PUT")
+	r, err := api.POST(payload, g.C.Request.Method == "PUT")
 	if err != nil {
 		g.SendMessage(http.StatusBadRequest, err.Error(), err)
 		return
 	}
+	model.RedactObject(r)
 
 	g.Send(http.StatusCreated, r)
 }
@@ -143,13 +152,15 @@ func GetRaw(c *gin.Context) {
 	var r interface{}
 
 	if c.Param("NAME") == "" {
-		r, err = api.List(ListOptions)
+		list, err := api.List(ListOptions)
 		if err != nil {
 			g.SendError(err)
 			return
 		}
+		model.RedactList(list)
+		r = list
 	} else {
-		r, err = api.GET(c.Param("NAME"), v1.GetOptions{})
+		obj, err := api.GET(c.Param("NAME"), v1.GetOptions{})
 		if err != nil {
 			if strings.HasSuffix(err.Error(), "not found") {
 				g.SendMessage(http.StatusNotFound, err.Error(), err)
@@ -158,6 +169,8 @@ func GetRaw(c *gin.Context) {
 			}
 			return
 		}
+		model.RedactObject(obj)
+		r = obj
 	}
 
 	g.Send(http.StatusOK, r)
@@ -191,9 +204,14 @@ func PatchRaw(c *gin.Context) {
 	}
 	api.SetNamespace(c.Param("NAMESPACE"))
 
-	var r interface{}
+	// restore redacted values
+	payload, err := model.RestoreRedactedPatch(g.C.Param("CLUSTER"), c.Param("GROUP"), c.Param("RESOURCE"), c.Param("NAMESPACE"), c.Param("NAME"), types.PatchType(c.ContentType()), c.Request.Body)
+	if err != nil {
+		g.SendMessage(http.StatusBadRequest, err.Error(), err)
+		return
+	}
 
-	r, err = api.PATCH(c.Param("NAME"), types.PatchType(c.ContentType()), c.Request.Body, v1.PatchOptions{})
+	r, err := api.PATCH(c.Param("NAME"), types.PatchType(c.ContentType()), payload, v1.PatchOptions{})
 	if err != nil {
 		if strings.HasSuffix(err.Error(), "not found") {
 			g.SendMessage(http.StatusNotFound, err.Error(), err)
@@ -202,6 +220,7 @@ func PatchRaw(c *gin.Context) {
 		}
 		return
 	}
+	model.RedactObject(r)
 
 	g.Send(http.StatusOK, r)
 
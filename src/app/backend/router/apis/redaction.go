@@ -0,0 +1,59 @@
+package apis
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kore3lab/dashboard/model"
+	"github.com/kore3lab/dashboard/pkg/app"
+	"github.com/kore3lab/dashboard/pkg/config"
+	"github.com/kore3lab/dashboard/pkg/lang"
+)
+
+// Reveal a redacted key (secrets, configmaps)
+func RevealKey(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	// url parameter validation
+	v := []string{"NAMESPACE", "RESOURCE", "NAME", "KEY"}
+	if err := g.ValidateUrl(v); err != nil {
+		g.SendMessage(http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	if c.Param("RESOURCE") != "secrets" && c.Param("RESOURCE") != "configmaps" {
+		g.SendMessage(http.StatusBadRequest, fmt.Sprintf("unsupported resource '%s'", c.Param("RESOURCE")), nil)
+		return
+	}
+
+	record := model.RevealRecord{
+		User:      lang.NVL(getUsername(c), "unknown"),
+		Address:   c.ClientIP(),
+		Cluster:   lang.NVL(g.C.Param("CLUSTER"), config.Cluster.DefaultContext),
+		Namespace: c.Param("NAMESPACE"),
+		Resource:  c.Param("RESOURCE"),
+		Name:      c.Param("NAME"),
+		Key:       c.Param("KEY"),
+	}
+
+	value, err := model.RevealKey(record)
+	if err != nil {
+		g.SendMessage(http.StatusNotFound, err.Error(), err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	g.Send(http.StatusOK, map[string]string{
+		"key":   record.Key,
+		"value": value,
+	})
+
+}
+
+// Get audit records of revealed keys
+func GetRevealRecords(c *gin.Context) {
+	g := app.Gin{C: c}
+
+	g.Send(http.StatusOK, model.GetRevealRecords())
+
+}
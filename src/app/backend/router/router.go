@@ -59,22 +59,81 @@ func CreateUrlMappings() {
 	// custom API
 	clustersAPI := Router.Group("/api/clusters/:CLUSTER", authenticate())
 	{
-		clustersAPI.GET("/metrics", apis.GetClusterMetrics)                                                // get metrics (cluster)
-		clustersAPI.GET("/nodes/:NAME/metrics", apis.GetNodeMetrics)                                       // get metrics (node)
-		clustersAPI.GET("/namespaces/:NAMESPACE/:RESOURCE/:NAME/metrics", apis.GetWorkloadMetrics)         // get metrics (workload - pod, deployment, statefulset, daemonset, replicaset)
-		clustersAPI.GET("/nodes/:NAME/pods", apis.GetNodePodListWithMetrics)                               // get pod list in (node)
-		clustersAPI.GET("/namespaces/:NAMESPACE/:RESOURCE/:NAME/pods", apis.GetWorkloadPodListWithMetrics) // get pod list in (workload - deployment, statefulset, daemonset, replicaset)
-		clustersAPI.GET("/graph/topology", apis.Topology)                                                  // get topology graph (cluster)
-		clustersAPI.GET("/graph/topology/namespaces/:NAMESPACE", apis.Topology)                            // get topology graph (namespace)
-		clustersAPI.GET("/graph/workloads", apis.Workloads)                                                // get workload graph (cluster)
-		clustersAPI.GET("/graph/workloads/namespaces/:NAMESPACE", apis.Workloads)                          // get workload graph (namespace)
-		clustersAPI.GET("/graph/network", apis.Network)                                                    // get network graph (cluster)
-		clustersAPI.GET("/graph/network/namespaces/:NAMESPACE", apis.Network)                              // get network graph (namespace)
-		clustersAPI.GET("/graph/pod/namespaces/:NAMESPACE/pods/:POD", apis.Pod)                            // get pod graph
-		clustersAPI.GET("/dashboard", apis.Dashboard)                                                      // get dashboard
-		clustersAPI.GET("/nodes", apis.GetNodeListWithUsage)                                               // get node-list
+		clustersAPI.GET("/metrics", apis.GetClusterMetrics)                                                        // get metrics (cluster)
+		clustersAPI.GET("/nodes/:NAME/metrics", apis.GetNodeMetrics)                                               // get metrics (node)
+		clustersAPI.GET("/namespaces/:NAMESPACE/:RESOURCE/:NAME/metrics", apis.GetWorkloadMetrics)                 // get metrics (workload - pod, deployment, statefulset, daemonset, replicaset, job, cronjob)
+		clustersAPI.GET("/namespaces/:NAMESPACE/:RESOURCE/:NAME/rightsizing", apis.GetWorkloadRightSizing)         // get right-sizing recommendations (workload - pod, deployment, statefulset, daemonset, replicaset, job, cronjob)
+		clustersAPI.GET("/nodes/:NAME/pods", apis.GetNodePodListWithMetrics)                                       // get pod list in (node)
+		clustersAPI.GET("/namespaces/:NAMESPACE/:RESOURCE/:NAME/pods", apis.GetWorkloadPodListWithMetrics)         // get pod list in (workload - deployment, statefulset, daemonset, replicaset, job, cronjob)
+		clustersAPI.GET("/graph/topology", apis.Topology)                                                          // get topology graph (cluster)
+		clustersAPI.GET("/graph/topology/namespaces/:NAMESPACE", apis.Topology)                                    // get topology graph (namespace)
+		clustersAPI.GET("/graph/workloads", apis.Workloads)                                                        // get workload graph (cluster)
+		clustersAPI.GET("/graph/workloads/namespaces/:NAMESPACE", apis.Workloads)                                  // get workload graph (namespace)
+		clustersAPI.GET("/graph/network", apis.Network)                                                            // get network graph (cluster)
+		clustersAPI.GET("/graph/network/namespaces/:NAMESPACE", apis.Network)                                      // get network graph (namespace)
+		clustersAPI.GET("/graph/storage", apis.Storage)                                                            // get storage graph (cluster)
+		clustersAPI.GET("/graph/storage/namespaces/:NAMESPACE", apis.Storage)                                      // get storage graph (namespace)
+		clustersAPI.GET("/graph/dependency", apis.Dependency)                                                      // get dependency graph (cluster)
+		clustersAPI.GET("/graph/dependency/namespaces/:NAMESPACE", apis.Dependency)                                // get dependency graph (namespace)
+		clustersAPI.GET("/graph/networkpolicy", apis.NetworkPolicy)                                                // get network-policy reachability graph (cluster)
+		clustersAPI.GET("/graph/networkpolicy/namespaces/:NAMESPACE", apis.NetworkPolicy)                          // get network-policy reachability graph (namespace)
+		clustersAPI.GET("/graph/networkpolicy/check", apis.NetworkPolicyCheck)                                     // can <from> talk to <to> on <port>
+		clustersAPI.GET("/graph/pod/namespaces/:NAMESPACE/pods/:POD", apis.Pod)                                    // get pod graph
+		clustersAPI.GET("/graph/rbac", apis.RBAC)                                                                  // get rbac graph (cluster)
+		clustersAPI.GET("/graph/rbac/namespaces/:NAMESPACE", apis.RBAC)                                            // get rbac graph (namespace)
+		clustersAPI.GET("/graph/rbac/whocan", apis.WhoCan)                                                         // who can <verb> <resource> (cluster)
+		clustersAPI.GET("/graph/rbac/whocan/namespaces/:NAMESPACE", apis.WhoCan)                                   // who can <verb> <resource> (namespace)
+		clustersAPI.GET("/dashboard", apis.Dashboard)                                                              // get dashboard (cluster)
+		clustersAPI.GET("/dashboard/namespaces/:NAMESPACE", apis.Dashboard)                                        // get dashboard (namespace)
+		clustersAPI.GET("/problems", apis.Problems)                                                                // get problems (cluster)
+		clustersAPI.GET("/problems/namespaces/:NAMESPACE", apis.Problems)                                          // get problems (namespace)
+		clustersAPI.GET("/nodes", apis.GetNodeListWithUsage)                                                       // get node-list
+		clustersAPI.GET("/customresources", apis.GetCustomResourceDefinitions)                                     // get custom resource definitions
+		clustersAPI.GET("/customresources/:CRD", apis.GetCustomResources)                                          // get custom resources (cluster)
+		clustersAPI.GET("/customresources/:CRD/namespaces/:NAMESPACE", apis.GetCustomResources)                    // get custom resources (namespace)
+		clustersAPI.GET("/openapi", apis.GetOpenAPIPaths)                                                          // get openapi v3 paths
+		clustersAPI.GET("/openapi/schema", apis.GetOpenAPISchema)                                                  // get openapi v3 schema (apiVersion)
+		clustersAPI.GET("/openapi/explain", apis.ExplainField)                                                     // explain a field (apiVersion, kind, field)
+		clustersAPI.POST("/openapi/validate", apis.ValidateManifest)                                               // validate manifests
+		clustersAPI.GET("/helm/releases", apis.GetHelmReleases)                                                    // get helm releases (cluster)
+		clustersAPI.GET("/helm/namespaces/:NAMESPACE/releases", apis.GetHelmReleases)                              // get helm releases (namespace)
+		clustersAPI.GET("/helm/namespaces/:NAMESPACE/releases/:NAME", apis.GetHelmRelease)                         // get a helm release (latest revision)
+		clustersAPI.GET("/helm/namespaces/:NAMESPACE/releases/:NAME/history", apis.GetHelmReleaseHistory)          // get a helm release history
+		clustersAPI.GET("/helm/namespaces/:NAMESPACE/releases/:NAME/revisions/:REVISION", apis.GetHelmRelease)     // get a helm release revision
+		clustersAPI.POST("/accessreview", apis.ReviewAccess)                                                       // review accesses ("can-i" : current user or a subject)
+		clustersAPI.GET("/accessreview/rules", apis.ReviewRules)                                                   // effective rules ("default" namespace)
+		clustersAPI.GET("/accessreview/namespaces/:NAMESPACE/rules", apis.ReviewRules)                             // effective rules (namespace)
+		clustersAPI.GET("/certificatesigningrequests", apis.GetCertificateSigningRequests)                         // get certificate signing requests (status)
+		clustersAPI.POST("/certificatesigningrequests", apis.CreateUserCertificate)                                // onboard a user (key, csr, approve and kubeconfig)
+		clustersAPI.POST("/certificatesigningrequests/:NAME/approve", apis.ApproveCertificateSigningRequest)       // approve a certificate signing request
+		clustersAPI.POST("/certificatesigningrequests/:NAME/deny", apis.DenyCertificateSigningRequest)             // deny a certificate signing request
+		clustersAPI.POST("/certificatesigningrequests/:NAME/kubeconfig", apis.GetUserCertificateKubeconfig)        // get a kubeconfig of an issued csr
+		clustersAPI.POST("/namespaces/:NAMESPACE/:RESOURCE/:NAME/debug", apis.CreateDebugContainer)                // inject an ephemeral debug container (pods)
+		clustersAPI.POST("/nodes/:NAME/shell", apis.CreateNodeShellGrant)                                          // issue a node shell grant for the terminal service (admin)
+		clustersAPI.POST("/namespaces/:NAMESPACE/:RESOURCE/:NAME/kubeconfig", apis.CreateServiceAccountKubeconfig) // create (or reuse) a service account and get a kubeconfig
+		clustersAPI.POST("/namespaces/:NAMESPACE/:RESOURCE/:NAME/keys", apis.AddKeys)                              // add keys (secrets, configmaps : json or multipart files)
+		clustersAPI.PUT("/namespaces/:NAMESPACE/:RESOURCE/:NAME/keys/:KEY", apis.PutKey)                           // add or replace a key
+		clustersAPI.DELETE("/namespaces/:NAMESPACE/:RESOURCE/:NAME/keys/:KEY", apis.DeleteKey)                     // delete a key
+		clustersAPI.POST("/namespaces/:NAMESPACE/:RESOURCE/:NAME/keys/:KEY/rename", apis.RenameKey)                // rename a key
+		clustersAPI.POST("/namespaces/:NAMESPACE/:RESOURCE/:NAME/keys/:KEY/reveal", apis.RevealKey)                // reveal a redacted key (secrets, configmaps)
+		clustersAPI.POST("/namespaces/:NAMESPACE/:RESOURCE/:NAME/run", apis.RunCronJob)                            // run a cronjob now (cronjobs)
+		clustersAPI.POST("/namespaces/:NAMESPACE/:RESOURCE/:NAME/suspend", apis.SuspendCronJob)                    // suspend a cronjob (cronjobs)
+		clustersAPI.POST("/namespaces/:NAMESPACE/:RESOURCE/:NAME/resume", apis.ResumeCronJob)                      // resume a cronjob (cronjobs)
+		clustersAPI.GET("/namespaces/:NAMESPACE/:RESOURCE/:NAME/jobs", apis.GetCronJobHistory)                     // get owned jobs (cronjobs)
 	}
 
+	// compare API (source, target : cluster, namespace, resource)
+	Router.POST("/api/compare", authenticate(), apis.Compare)
+
+	// copy API (source, target : cluster, namespace, resources)
+	Router.POST("/api/copy", authenticate(), apis.Copy)
+
+	// fleet API (health summary of all contexts)
+	Router.GET("/api/fleet", authenticate(), apis.GetFleet)
+
+	// audit API
+	Router.GET("/api/audit/reveals", authenticate(), apis.GetRevealRecords) // revealed keys (secrets, configmaps)
+
 	// RAW-API > POST/PUT (apply, patch)
 	Router.POST("/raw/clusters/:CLUSTER", authenticate(), apis.ApplyRaw)
 	Router.PUT("/raw/clusters/:CLUSTER", authenticate(), apis.ApplyRaw)
@@ -32,7 +32,7 @@ func clusterHandler(db *sql.DB) http.HandlerFunc {
 		}
 
 		app := App{Writer: w}
-		metrics, err := sidedb.Select(db, "nodes", cluster, "", "", "SUM")
+		metrics, err := sidedb.Select(db, "nodes", cluster, "", "", "", "SUM")
 		if err != nil {
 			app.Send(nil)
 		} else {
@@ -54,7 +54,7 @@ func nodeHandler(db *sql.DB) http.HandlerFunc {
 		}
 
 		app := App{Writer: w}
-		metrics, err := sidedb.Select(db, "nodes", cluster, "", vars["NAME"], "SUM")
+		metrics, err := sidedb.Select(db, "nodes", cluster, "", vars["NAME"], "", "SUM")
 		if err != nil {
 			app.Send(nil)
 		} else {
@@ -77,7 +77,7 @@ func podHandler(db *sql.DB) http.HandlerFunc {
 		}
 
 		app := App{Writer: w}
-		metrics, err := sidedb.Select(db, "pods", cluster, vars["NAMESPACE"], vars["NAME"], vars["OP"])
+		metrics, err := sidedb.Select(db, "pods", cluster, vars["NAMESPACE"], vars["NAME"], r.URL.Query().Get("container"), vars["OP"])
 		if err != nil {
 			app.Send(nil)
 		} else {
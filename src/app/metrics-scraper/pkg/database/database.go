@@ -133,13 +133,17 @@ func CullDatabase(db *sql.DB, cluster string, window *time.Duration) error { //
 	return nil
 }
 
-func Select(db *sql.DB, table string, cluster string, namespace string, names string, op string) ([]Point, error) {
+func Select(db *sql.DB, table string, cluster string, namespace string, names string, container string, op string) ([]Point, error) { // customized by kore-board (add "container" filter)
 
 	if op == "" {
 		op = "SUM"
 	}
+	op = strings.ToUpper(op)
+	if op != "SUM" && op != "AVG" && op != "MAX" && op != "MIN" {
+		return nil, fmt.Errorf("unsupported function '%s'", op)
+	}
 
-	sql := fmt.Sprintf("SELECT time, CAST(%s(cpu) AS INTEGER) cpu, CAST(%s(memory) AS INTEGER) memory FROM %s WHERE cluster=?", op, op, table)
+	sql := fmt.Sprintf("SELECT time, CAST(%s(CAST(cpu AS INTEGER)) AS INTEGER) cpu, CAST(%s(CAST(memory AS INTEGER)) AS INTEGER) memory FROM %s WHERE cluster=?", op, op, table) // customized by kore-board (numeric MAX, MIN of text columns)
 	params := []interface{}{cluster}
 
 	if table == "pods" {
@@ -164,6 +168,12 @@ func Select(db *sql.DB, table string, cluster string, namespace string, names st
 		}
 	}
 
+	// container
+	if table == "pods" && container != "" {
+		sql = sql + " AND container=?"
+		params = append(params, container)
+	}
+
 	sql = sql + " GROUP BY time ORDER BY time"
 
 	log.Infof("sql=%s, params=%v", sql, params)
@@ -116,7 +116,7 @@ var _ = Describe("Database functions", func() {
 			nm := nodeMetrics()
 			pm := podMetrics()
 
-			err = sideDb.UpdateDatabase(db, &nm, &pm)
+			err = sideDb.UpdateDatabase(db, "testing", &nm, &pm)
 			if err != nil {
 				panic(err.Error())
 			}
@@ -180,12 +180,12 @@ var _ = Describe("Database functions", func() {
 			nm := nodeMetrics()
 			pm := podMetrics()
 
-			err = sideDb.UpdateDatabase(db, &nm, &pm)
+			err = sideDb.UpdateDatabase(db, "testing", &nm, &pm)
 			if err != nil {
 				panic(err.Error())
 			}
 
-			sqlStmt := "insert into nodes(name,cpu,memory,storage,time) values('lame','1000','100000','0',datetime('now','-20 minutes'));"
+			sqlStmt := "insert into nodes(cluster,name,cpu,memory,storage,time) values('testing','lame','1000','100000','0',datetime('now','-20 minutes'));"
 			_, err = db.Exec(sqlStmt)
 			if err != nil {
 				panic(err.Error())
@@ -196,7 +196,7 @@ var _ = Describe("Database functions", func() {
 				panic(err.Error())
 			}
 
-			err = sideDb.CullDatabase(db, &timeWindow)
+			err = sideDb.CullDatabase(db, "testing", &timeWindow)
 			if err != nil {
 				panic(err.Error())
 			}
@@ -223,5 +223,62 @@ var _ = Describe("Database functions", func() {
 			}
 
 		})
+		It("should select pod metrics of a container.", func() {
+			db, err := sql.Open("sqlite3", ":memory:")
+			if err != nil {
+				panic(err.Error())
+			}
+			defer db.Close()
+
+			err = sideDb.CreateDatabase(db)
+			if err != nil {
+				panic(err.Error())
+			}
+
+			nm := nodeMetrics()
+			pm := podMetrics()
+
+			err = sideDb.UpdateDatabase(db, "testing", &nm, &pm)
+			if err != nil {
+				panic(err.Error())
+			}
+
+			points, err := sideDb.Select(db, "pods", "testing", "", "testing", "container_test", "MAX")
+			Expect(err).To(BeNil())
+			Expect(points).To(HaveLen(1))
+
+			points, err = sideDb.Select(db, "pods", "testing", "", "testing", "container_none", "MAX")
+			Expect(err).To(BeNil())
+			Expect(points).To(BeEmpty())
+
+			_, err = sideDb.Select(db, "pods", "testing", "", "testing", "", "SUM(cpu)) FROM pods; --")
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should select numeric max of pod metrics.", func() {
+			db, err := sql.Open("sqlite3", ":memory:")
+			if err != nil {
+				panic(err.Error())
+			}
+			defer db.Close()
+
+			err = sideDb.CreateDatabase(db)
+			if err != nil {
+				panic(err.Error())
+			}
+
+			for _, v := range []string{"9", "10"} {
+				_, err = db.Exec("insert into pods(cluster, uid, name, namespace, container, cpu, memory, storage, time) values(?, ?, ?, ?, ?, ?, ?, ?, datetime('now'))", "testing", v, "testing", "default", "container_test", v, v, "0")
+				if err != nil {
+					panic(err.Error())
+				}
+			}
+
+			points, err := sideDb.Select(db, "pods", "testing", "default", "testing", "container_test", "MAX")
+			Expect(err).To(BeNil())
+			Expect(points).To(HaveLen(1))
+			Expect(points[0].CPU).To(Equal(uint64(10)))
+			Expect(points[0].Memory).To(Equal(uint64(10)))
+		})
 	})
 })
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kore3lab/dashboard/terminal/pkg/config"
+	"github.com/kore3lab/dashboard/terminal/pkg/randomstring"
+	log "github.com/sirupsen/logrus"
+	coreV1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	nodeShellContainer       = "shell"
+	nodeShellRunTimeout      = 60 * time.Second
+	nodeShellConnectTimeout  = 60 * time.Second // a pod is deleted if a session token is not used in time
+	nodeShellMaxSeconds      = int64(12 * 3600) // safety net : the shell is killed (pod "Failed", not deleted) even if a session is not closed
+	nodeShellTokenExpiration = 2 * nodeShellConnectTimeout
+	nodeShellLabel           = "app.kubernetes.io/component" // label of node shell pods (value "node-shell")
+)
+
+// node shell options (set by startup parameters)
+var nodeShellImage = "busybox:stable"
+var nodeShellNamespace = "default"
+var nodeShellKey = "" // grant signing key (shared with the backend, empty : disabled)
+
+// claims of a grant issued by the backend : base64url(json) "." base64url(hmac-sha256)
+type nodeShellClaims struct {
+	Cluster   string `json:"cluster"`
+	Node      string `json:"node"`
+	User      string `json:"user"`
+	ExpiredAt int64  `json:"expiredAt"`
+}
+
+// verify a grant issued by the backend to a signed-in admin user (returns the user)
+func verifyNodeShellGrant(grant string, cluster string, node string) (string, error) {
+
+	if nodeShellKey == "" {
+		return "", errors.New("node shell is disabled (startup parameter 'node-shell-key' is empty)")
+	}
+
+	parts := strings.Split(grant, ".")
+	if len(parts) != 2 {
+		return "", errors.New("invalid node shell grant")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", errors.New("invalid node shell grant")
+	}
+	mac := hmac.New(sha256.New, []byte(nodeShellKey))
+	mac.Write([]byte(parts[0]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return "", errors.New("invalid node shell grant signature")
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", errors.New("invalid node shell grant")
+	}
+	claims := nodeShellClaims{}
+	if err := json.Unmarshal(b, &claims); err != nil {
+		return "", errors.New("invalid node shell grant")
+	}
+	if claims.Cluster != cluster || claims.Node != node {
+		return "", fmt.Errorf("node shell grant is not for '%s/%s'", cluster, node)
+	}
+	if time.Now().Unix() > claims.ExpiredAt {
+		return "", errors.New("node shell grant expired")
+	}
+	return claims.User, nil
+
+}
+
+// a node shell pod is reachable only by termtype "node" (with a grant), other termtypes (container, pod, debug) would open the host without a grant
+func verifyNotNodeShellPod(cluster string, namespace string, name string) error {
+
+	api, err := newKubernetesClient(cluster)
+	if err != nil {
+		return err
+	}
+	pod, err := api.CoreV1().Pods(namespace).Get(context.TODO(), name, metaV1.GetOptions{})
+	if apiErrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if pod.Labels[nodeShellLabel] == "node-shell" {
+		return fmt.Errorf("pod '%s/%s' is a node shell pod", namespace, name)
+	}
+	return nil
+
+}
+
+// api-client of a context
+func newKubernetesClient(cluster string) (*kubernetes.Clientset, error) {
+
+	var restConfig *rest.Config
+	var err error
+	if config.Value.IsRunningInCluster {
+		restConfig = config.Value.InClusterConfig
+	} else if restConfig, err = config.KubeConfigs(cluster); err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(restConfig)
+
+}
+
+// schedule a privileged pod (hostPID, hostNetwork) on a node and "nsenter" into the host
+func createNodeShellPod(cluster string, node string) (string, error) {
+
+	api, err := newKubernetesClient(cluster)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := api.CoreV1().Nodes().Get(context.TODO(), node, metaV1.GetOptions{}); err != nil {
+		return "", err
+	}
+
+	privileged := true
+	deadline := nodeShellMaxSeconds
+	pod := &coreV1.Pod{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name:      fmt.Sprintf("node-shell-%s", strings.ToLower(randomstring.Generate(5))),
+			Namespace: nodeShellNamespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "kore-board",
+				nodeShellLabel:                 "node-shell",
+			},
+		},
+		Spec: coreV1.PodSpec{
+			NodeName:              node,
+			HostPID:               true,
+			HostNetwork:           true,
+			HostIPC:               true,
+			RestartPolicy:         coreV1.RestartPolicyNever,
+			ActiveDeadlineSeconds: &deadline,
+			Tolerations:           []coreV1.Toleration{{Operator: coreV1.TolerationOpExists}},
+			Containers: []coreV1.Container{{
+				Name:            nodeShellContainer,
+				Image:           nodeShellImage,
+				ImagePullPolicy: coreV1.PullIfNotPresent,
+				Command:         []string{"nsenter", "--target", "1", "--mount", "--uts", "--ipc", "--net", "--pid", "--", "sh", "-c", "bash -l 2>/dev/null || sh -l"},
+				Stdin:           true,
+				TTY:             true,
+				SecurityContext: &coreV1.SecurityContext{Privileged: &privileged},
+			}},
+		},
+	}
+	if pod, err = api.CoreV1().Pods(nodeShellNamespace).Create(context.TODO(), pod, metaV1.CreateOptions{}); err != nil {
+		return "", err
+	}
+
+	// wait for running
+	err = wait.PollImmediate(time.Second, nodeShellRunTimeout, func() (bool, error) {
+		p, err := api.CoreV1().Pods(nodeShellNamespace).Get(context.TODO(), pod.Name, metaV1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		if p.Status.Phase == coreV1.PodFailed || p.Status.Phase == coreV1.PodSucceeded {
+			return false, fmt.Errorf("node shell pod '%s' is terminated (phase=%s)", pod.Name, p.Status.Phase)
+		}
+		return p.Status.Phase == coreV1.PodRunning, nil
+	})
+	if err != nil {
+		deleteNodeShellPod(cluster, pod.Name)
+		return "", err
+	}
+
+	return pod.Name, nil
+
+}
+
+// delete a node shell pod if a session token is not used (expired) in time
+func expireNodeShellToken(cluster string, name string, token string) {
+
+	time.AfterFunc(nodeShellConnectTimeout, func() {
+		if instSvr.Cache.Get(token) != nil {
+			instSvr.Cache.Delete(token)
+			log.Warnf("Node shell session token of '%s/%s' is not used in %v", nodeShellNamespace, name, nodeShellConnectTimeout)
+			deleteNodeShellPod(cluster, name)
+		}
+	})
+
+}
+
+// delete a node shell pod (on session end)
+func deleteNodeShellPod(cluster string, name string) {
+
+	api, err := newKubernetesClient(cluster)
+	if err == nil {
+		grace := int64(0)
+		err = api.CoreV1().Pods(nodeShellNamespace).Delete(context.TODO(), name, metaV1.DeleteOptions{GracePeriodSeconds: &grace})
+	}
+	if err != nil {
+		log.Errorf("Unable to delete a node shell pod '%s/%s' (cause=%v)", nodeShellNamespace, name, err)
+	} else {
+		log.Infof("Node shell pod '%s/%s' deleted", nodeShellNamespace, name)
+	}
+
+}
@@ -174,6 +174,10 @@ elif [ "${ARG_TERM_TYPE}" == "pod" ];then
     /usr/bin/kubectl exec --kubeconfig .kube/config --stdin --tty --namespace=${ARG_NAMESPACE} ${ARG_POD} -- /bin/bash || /usr/bin/kubectl exec --kubeconfig .kube/config --stdin --tty --namespace=${ARG_NAMESPACE} ${ARG_POD} -- /bin/sh || echo "remote shell is not supported"
 elif [ "${ARG_TERM_TYPE}" == "container" ];then
     /usr/bin/kubectl exec --kubeconfig .kube/config --stdin --tty --namespace=${ARG_NAMESPACE} ${ARG_POD} --container ${ARG_CONTAINER} -- /bin/bash || /usr/bin/kubectl exec --kubeconfig .kube/config --stdin --tty --namespace=${ARG_NAMESPACE} ${ARG_POD} --container ${ARG_CONTAINER} -- /bin/sh || echo "remote shell is not supported"
+elif [ "${ARG_TERM_TYPE}" == "debug" ];then
+    /usr/bin/kubectl attach --kubeconfig .kube/config --stdin --tty --namespace=${ARG_NAMESPACE} ${ARG_POD} --container ${ARG_CONTAINER} || echo "unable to attach to a debug container"
+elif [ "${ARG_TERM_TYPE}" == "node" ];then
+    /usr/bin/kubectl attach --kubeconfig .kube/config --stdin --tty --namespace=${ARG_NAMESPACE} ${ARG_POD} --container ${ARG_CONTAINER} || echo "unable to attach to a node shell"
 else
     echo "term type argument error"
     exit 1    
@@ -69,6 +69,9 @@ func main() {
 	kubeconfig = flag.String("kubeconfig", "", "The path to the kubeconfig used to connect to the Kubernetes API server and the Kubelets (defaults to in-cluster config)")
 	logLevel = flag.String("log-level", "debug", "The log level")
 	corsonoff = flag.String("corsonoff", "on", "CORS(Cross-Origin Resource Sharing) on/off (defaults to on(blocked by CORS))")
+	flag.StringVar(&nodeShellImage, "node-shell-image", NVL(os.Getenv("NODE_SHELL_IMAGE"), nodeShellImage), "The image of a node shell pod (requires 'nsenter')")
+	flag.StringVar(&nodeShellNamespace, "node-shell-namespace", NVL(os.Getenv("NODE_SHELL_NAMESPACE"), nodeShellNamespace), "The namespace of node shell pods")
+	flag.StringVar(&nodeShellKey, "node-shell-key", os.Getenv("NODE_SHELL_KEY"), "The key to verify node shell grants (shared with the backend, empty : node shell disabled)")
 
 	flag.Parse()
 
@@ -113,6 +116,7 @@ func main() {
 	r.HandleFunc("/api/terminal/clusters/{CLUSTER}/termtype/{TERMTYPE}", ProcTerminal).Methods("GET")
 	r.HandleFunc("/api/terminal/clusters/{CLUSTER}/namespaces/{NAMESPACE}/pods/{POD}/termtype/{TERMTYPE}", ProcTerminal).Methods("GET")
 	r.HandleFunc("/api/terminal/clusters/{CLUSTER}/namespaces/{NAMESPACE}/pods/{POD}/containers/{CONTAINER}/termtype/{TERMTYPE}", ProcTerminal).Methods("GET")
+	r.HandleFunc("/api/terminal/clusters/{CLUSTER}/nodes/{NODE}/termtype/{TERMTYPE}", ProcTerminal).Methods("GET")
 	r.HandleFunc("/api/terminal/ws", generateHandleWS)
 	r.HandleFunc("/api/v1/config", LoadConfig).Methods("PATCH")
 	r.HandleFunc("/healthy", healthy).Methods("GET") // healthy
@@ -161,9 +165,46 @@ func ProcTerminal(w http.ResponseWriter, r *http.Request) {
 	termreq.container = NVL(vars["CONTAINER"], "")
 	termreq.termtype = NVL(vars["TERMTYPE"], "container")
 
+	// node shell : a privileged pod on the node (requires a grant issued by the backend)
+	if termreq.termtype == "node" {
+		if vars["NODE"] == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("node shell requires a node"))
+			return
+		}
+		user, err := verifyNodeShellGrant(r.URL.Query().Get("grant"), termreq.cluster, vars["NODE"])
+		if err != nil {
+			log.Warnf("Unable to open a node shell '%s/%s' (cause=%v)", termreq.cluster, vars["NODE"], err)
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		log.Infof("Node shell '%s/%s' is requested by '%s'", termreq.cluster, vars["NODE"], user)
+		pod, err := createNodeShellPod(termreq.cluster, vars["NODE"])
+		if err != nil {
+			log.Errorf("Unable to create a node shell pod (cause=%v)", err)
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		termreq.namespace = nodeShellNamespace
+		termreq.pod = pod
+		termreq.container = nodeShellContainer
+	} else if termreq.pod != "" {
+		if err := verifyNotNodeShellPod(termreq.cluster, termreq.namespace, termreq.pod); err != nil {
+			log.Warnf("Unable to open a terminal '%s/%s/%s' (cause=%v)", termreq.cluster, termreq.namespace, termreq.pod, err)
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(err.Error()))
+			return
+		}
+	}
+
 	err := getContext(w, r, termreq)
 	if err != nil {
 		log.Errorf("%v", err)
+		if termreq.termtype == "node" {
+			deleteNodeShellPod(termreq.cluster, termreq.pod)
+		}
 		return
 	} else {
 		makeAuthToken(w, r, termreq)
@@ -235,9 +276,18 @@ func makeAuthToken(w http.ResponseWriter, r *http.Request, req *termRequest) {
 	ttyParameter.Arg = make(map[string]string)
 	setTtyValue(req, ttyParameter.Arg)
 
+	// node shell : a pod is deleted if the token is not used in time
+	expiration := time.Duration(cache.DefaultExpiration)
+	if req.termtype == "node" {
+		expiration = nodeShellTokenExpiration
+	}
+
 	//캐시 등록
-	if err := instSvr.Cache.Add(token, &ttyParameter, cache.DefaultExpiration); err != nil {
+	if err := instSvr.Cache.Add(token, &ttyParameter, expiration); err != nil {
 		log.Errorf("save token and ttyParam err:%v", err)
+		if req.termtype == "node" {
+			deleteNodeShellPod(req.cluster, req.pod)
+		}
 		msg := fmt.Sprint("save token and ttyParam err")
 		_, err := w.Write([]byte(msg))
 		if err != nil {
@@ -246,6 +296,10 @@ func makeAuthToken(w http.ResponseWriter, r *http.Request, req *termRequest) {
 		return
 	}
 
+	if req.termtype == "node" {
+		expireNodeShellToken(req.cluster, req.pod, token)
+	}
+
 	// Go 데이타
 	mem := Response{true, token}
 
@@ -406,6 +460,11 @@ func processWSConn(ctx context.Context, conn *websocket.Conn) error {
 		return errors.New("ERROR:No Token Provided")
 	}
 
+	// delete a node shell pod when the session ends
+	if params["termtype"] == "node" && params["pod"] != "" {
+		defer deleteNodeShellPod(params["cluster"], params["pod"])
+	}
+
 	//Backend Slave생성
 	var slave server.Slave
 	slave, err = instSvr.Factory.New(params)